@@ -0,0 +1,20 @@
+package workos
+
+import "strings"
+
+// EmailDomain returns the lowercased domain of the email, or an empty string
+// when the email is malformed.
+func EmailDomain(email string) string {
+	email = strings.TrimSpace(email)
+
+	i := strings.LastIndex(email, "@")
+	if i <= 0 || i == len(email)-1 || strings.Contains(email[:i], "@") {
+		return ""
+	}
+
+	domain := email[i+1:]
+	if strings.ContainsAny(domain, " \t\r\n") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return ""
+	}
+	return strings.ToLower(domain)
+}
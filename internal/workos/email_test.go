@@ -0,0 +1,35 @@
+package workos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailDomain(t *testing.T) {
+	tests := []struct {
+		scenario string
+		email    string
+		expected string
+	}{
+		{scenario: "normal", email: "marcelina@foo-corp.com", expected: "foo-corp.com"},
+		{scenario: "uppercase", email: "Marcelina@Foo-Corp.COM", expected: "foo-corp.com"},
+		{scenario: "subdomain", email: "marcelina@eu.mail.foo-corp.com", expected: "eu.mail.foo-corp.com"},
+		{scenario: "plus addressing", email: "marcelina+sso@foo-corp.com", expected: "foo-corp.com"},
+		{scenario: "surrounding spaces", email: " marcelina@foo-corp.com ", expected: "foo-corp.com"},
+		{scenario: "empty"},
+		{scenario: "missing at sign", email: "marcelina.foo-corp.com"},
+		{scenario: "missing local part", email: "@foo-corp.com"},
+		{scenario: "missing domain", email: "marcelina@"},
+		{scenario: "several at signs", email: "marcelina@davis@foo-corp.com"},
+		{scenario: "empty label", email: "marcelina@foo-corp..com"},
+		{scenario: "trailing dot", email: "marcelina@foo-corp.com."},
+		{scenario: "space in domain", email: "marcelina@foo corp.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			require.Equal(t, test.expected, EmailDomain(test.email))
+		})
+	}
+}
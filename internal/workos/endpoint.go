@@ -0,0 +1,31 @@
+package workos
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DefaultEndpoint is the WorkOS API endpoint used by clients without an
+// Endpoint.
+const DefaultEndpoint = "https://api.workos.com"
+
+// NormalizeEndpoint returns the endpoint without surrounding spaces and
+// trailing slashes, so paths can be appended to it, or defaultEndpoint when it
+// is empty.
+func NormalizeEndpoint(endpoint, defaultEndpoint string) string {
+	endpoint = strings.TrimRight(strings.TrimSpace(endpoint), "/")
+	if endpoint == "" {
+		return defaultEndpoint
+	}
+	return endpoint
+}
+
+// validateURL returns an error when the URL of a request is not an absolute
+// HTTP URL, which usually means the Endpoint of the client is invalid.
+func validateURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid endpoint %q: the endpoint must be an absolute http or https URL", u.Scheme+"://"+u.Host)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package workos
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeEndpoint(t *testing.T) {
+	tests := []struct {
+		scenario string
+		endpoint string
+		expected string
+	}{
+		{scenario: "empty endpoint", expected: DefaultEndpoint},
+		{scenario: "blank endpoint", endpoint: " ", expected: DefaultEndpoint},
+		{scenario: "endpoint", endpoint: "https://api.workos.com", expected: "https://api.workos.com"},
+		{scenario: "trailing slash", endpoint: "https://api.workos.com/", expected: "https://api.workos.com"},
+		{scenario: "trailing slashes", endpoint: "https://proxy.foo-corp.com/workos//", expected: "https://proxy.foo-corp.com/workos"},
+		{scenario: "surrounding spaces", endpoint: " https://api.workos.com/ ", expected: "https://api.workos.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			require.Equal(t, test.expected, NormalizeEndpoint(test.endpoint, DefaultEndpoint))
+		})
+	}
+}
+
+func TestDoInvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"api.workos.com/users", "ftp://api.workos.com/users", "/users"} {
+		req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+		require.NoError(t, err)
+
+		_, err = Do(http.DefaultClient, nil, nil, req)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "the endpoint must be an absolute http or https URL")
+	}
+}
@@ -0,0 +1,29 @@
+package workos
+
+import (
+	"log"
+	"time"
+
+	"github.com/workos/workos-go/v4/pkg/common"
+)
+
+// LogObserver returns an observer that logs every request with the given
+// logger before notifying next, if any. It returns next when logger is nil.
+func LogObserver(logger *log.Logger, next common.Observer) common.Observer {
+	if logger == nil {
+		return next
+	}
+	return logObserver{logger: logger, next: next}
+}
+
+type logObserver struct {
+	logger *log.Logger
+	next   common.Observer
+}
+
+func (o logObserver) ObserveRequest(endpoint string, status int, dur time.Duration) {
+	o.logger.Printf("workos: %s: status %d in %s", endpoint, status, dur)
+	if o.next != nil {
+		o.next.ObserveRequest(endpoint, status, dur)
+	}
+}
@@ -0,0 +1,31 @@
+package workos
+
+import (
+	"bytes"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogObserver(t *testing.T) {
+	var observations []string
+	next := observerFunc(func(endpoint string, status int, dur time.Duration) {
+		observations = append(observations, endpoint)
+	})
+
+	require.Nil(t, LogObserver(nil, nil))
+	require.NotNil(t, LogObserver(nil, next))
+
+	var buf bytes.Buffer
+	observer := LogObserver(log.New(&buf, "", 0), next)
+	observer.ObserveRequest("/users/{id}", 200, time.Second)
+
+	require.Equal(t, "workos: /users/{id}: status 200 in 1s\n", buf.String())
+	require.Equal(t, []string{"/users/{id}"}, observations)
+
+	buf.Reset()
+	LogObserver(log.New(&buf, "", 0), nil).ObserveRequest("/users", 0, time.Second)
+	require.Equal(t, "workos: /users: status 0 in 1s\n", buf.String())
+}
@@ -0,0 +1,29 @@
+package workos
+
+import "fmt"
+
+// Limits of the metadata of Users and Organizations enforced by the WorkOS
+// API.
+const (
+	MaxMetadataKeys        = 10
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 500
+)
+
+// ValidateMetadata ensures the metadata fits in the WorkOS API limits.
+func ValidateMetadata(m map[string]string) error {
+	if len(m) > MaxMetadataKeys {
+		return fmt.Errorf("invalid metadata: %d keys exceed the limit of %d", len(m), MaxMetadataKeys)
+	}
+
+	for k, v := range m {
+		if len(k) > MaxMetadataKeyLength {
+			return fmt.Errorf("invalid metadata key %q: longer than %d characters", k, MaxMetadataKeyLength)
+		}
+		if len(v) > MaxMetadataValueLength {
+			return fmt.Errorf("invalid metadata value for key %q: longer than %d characters", k, MaxMetadataValueLength)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package workos
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	require.NoError(t, ValidateMetadata(nil))
+	require.NoError(t, ValidateMetadata(map[string]string{"key": "value"}))
+
+	tooMany := map[string]string{}
+	for i := 0; i <= MaxMetadataKeys; i++ {
+		tooMany["key"+strconv.Itoa(i)] = "value"
+	}
+	require.EqualError(t, ValidateMetadata(tooMany), "invalid metadata: 11 keys exceed the limit of 10")
+
+	err := ValidateMetadata(map[string]string{strings.Repeat("k", MaxMetadataKeyLength+1): "value"})
+	require.Error(t, err)
+
+	err = ValidateMetadata(map[string]string{"key": strings.Repeat("v", MaxMetadataValueLength+1)})
+	require.EqualError(t, err, `invalid metadata value for key "key": longer than 500 characters`)
+}
@@ -0,0 +1,22 @@
+package workos
+
+import "strings"
+
+// FullName joins the non-empty names, falling back to the local part of the
+// email when both are empty.
+func FullName(firstName, lastName, email string) string {
+	var names []string
+	for _, n := range []string{firstName, lastName} {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	if len(names) > 0 {
+		return strings.Join(names, " ")
+	}
+
+	if i := strings.LastIndex(email, "@"); i >= 0 {
+		return email[:i]
+	}
+	return email
+}
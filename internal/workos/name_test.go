@@ -0,0 +1,29 @@
+package workos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFullName(t *testing.T) {
+	tests := []struct {
+		scenario  string
+		firstName string
+		lastName  string
+		email     string
+		expected  string
+	}{
+		{scenario: "both names", firstName: "Marcelina", lastName: "Davis", email: "marcelina@foo-corp.com", expected: "Marcelina Davis"},
+		{scenario: "first name only", firstName: "Marcelina", email: "marcelina@foo-corp.com", expected: "Marcelina"},
+		{scenario: "last name only", lastName: "Davis", email: "marcelina@foo-corp.com", expected: "Davis"},
+		{scenario: "blank names", firstName: " ", lastName: "", email: "marcelina@foo-corp.com", expected: "marcelina"},
+		{scenario: "all empty"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			require.Equal(t, test.expected, FullName(test.firstName, test.lastName, test.email))
+		})
+	}
+}
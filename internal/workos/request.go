@@ -0,0 +1,119 @@
+package workos
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/workos/workos-go/v4/pkg/common"
+	"github.com/workos/workos-go/v4/pkg/workos_errors"
+)
+
+// SetCorrelationID forwards the correlation ID carried by the request context,
+// if any.
+func SetCorrelationID(req *http.Request) {
+	if id := common.CorrelationID(req.Context()); id != "" {
+		req.Header.Set(common.CorrelationIDHeader, id)
+	}
+}
+
+// NewHTTPClient returns an http.Client with the given timeout and its own
+// transport, so its idle connections can be closed without affecting other
+// clients.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	var transport http.RoundTripper
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = t.Clone()
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
+// WithTimeout returns a copy of the given http.Client with the given timeout,
+// or a new http.Client when it is nil, so that the given one is left
+// untouched.
+func WithTimeout(client *http.Client, timeout time.Duration) *http.Client {
+	if client == nil {
+		return NewHTTPClient(timeout)
+	}
+
+	c := *client
+	c.Timeout = timeout
+	return &c
+}
+
+// CloseIdleConnections closes the idle connections of the given http.Client
+// when it still uses the transport created along with it by NewHTTPClient, so
+// that user-supplied clients and transports are left untouched.
+func CloseIdleConnections(client *http.Client, ownedTransport http.RoundTripper) {
+	if ownedTransport != nil && client != nil && client.Transport == ownedTransport {
+		client.CloseIdleConnections()
+	}
+}
+
+// Do applies the editors to the request, sends it with the given http.Client
+// and reports it to the observer, if any. The request is not sent when an
+// editor fails or when, once edited, its bearer token is empty or its URL is
+// not an absolute HTTP URL.
+func Do(
+	client *http.Client,
+	observer common.Observer,
+	editors []func(*http.Request) error,
+	req *http.Request,
+) (*http.Response, error) {
+	for _, edit := range editors {
+		if err := edit(req); err != nil {
+			return nil, err
+		}
+	}
+
+	if strings.TrimSpace(req.Header.Get("Authorization")) == "Bearer" {
+		return nil, workos_errors.ErrMissingAPIKey
+	}
+
+	if err := validateURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := client.Do(req)
+
+	if observer != nil {
+		status := 0
+		if res != nil {
+			status = res.StatusCode
+		}
+		observer.ObserveRequest(routeTemplate(req.URL.Path), status, time.Since(start))
+	}
+
+	return res, err
+}
+
+// routeTemplate replaces the ID segments of the given path with "{id}" so
+// observers can group requests by endpoint. A segment is an ID when it
+// contains a character other than a letter or an underscore, or when it
+// follows an "external_id" segment.
+func routeTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if s == "" {
+			continue
+		}
+		if i > 0 && segments[i-1] == "external_id" || !isStaticSegment(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+func isStaticSegment(s string) bool {
+	for _, r := range s {
+		if r != '_' && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
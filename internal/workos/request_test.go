@@ -0,0 +1,201 @@
+package workos
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/workos/workos-go/v4/pkg/common"
+	"github.com/workos/workos-go/v4/pkg/workos_errors"
+)
+
+func TestSetCorrelationID(t *testing.T) {
+	t.Run("header is set when the context carries an ID", func(t *testing.T) {
+		ctx := common.WithCorrelationID(context.Background(), "corr_123")
+		req, err := http.NewRequest(http.MethodGet, "https://api.workos.com", nil)
+		require.NoError(t, err)
+
+		req = req.WithContext(ctx)
+		SetCorrelationID(req)
+		require.Equal(t, "corr_123", req.Header.Get("X-Correlation-ID"))
+	})
+
+	t.Run("header is omitted otherwise", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "https://api.workos.com", nil)
+		require.NoError(t, err)
+
+		SetCorrelationID(req)
+		_, ok := req.Header["X-Correlation-Id"]
+		require.False(t, ok)
+	})
+}
+
+type observation struct {
+	endpoint string
+	status   int
+}
+
+type observerFunc func(endpoint string, status int, dur time.Duration)
+
+func (f observerFunc) ObserveRequest(endpoint string, status int, dur time.Duration) {
+	f(endpoint, status, dur)
+}
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var observations []observation
+	observer := observerFunc(func(endpoint string, status int, dur time.Duration) {
+		observations = append(observations, observation{endpoint: endpoint, status: status})
+	})
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/organizations", nil)
+	require.NoError(t, err)
+
+	res, err := Do(server.Client(), observer, nil, req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, []observation{{endpoint: "/organizations", status: http.StatusCreated}}, observations)
+
+	req, err = http.NewRequest(http.MethodGet, "http://127.0.0.1:0/users/user_123", nil)
+	require.NoError(t, err)
+
+	_, err = Do(server.Client(), observer, nil, req)
+	require.Error(t, err)
+	require.Equal(t, observation{endpoint: "/users/{id}", status: 0}, observations[1])
+
+	req, err = http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	res, err = Do(server.Client(), nil, nil, req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Len(t, observations, 2)
+}
+
+func TestRouteTemplate(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/organizations", expected: "/organizations"},
+		{path: "/organizations/org_01EHZNVPK3SFK441A1RGBFSHRT", expected: "/organizations/{id}"},
+		{path: "/Organizations/org_123", expected: "/Organizations/{id}"},
+		{path: "/user_management/users/user_123/email_verification/send", expected: "/user_management/users/{id}/email_verification/send"},
+		{path: "/user_management/organization_memberships/om_123/deactivate", expected: "/user_management/organization_memberships/{id}/deactivate"},
+		{path: "/user_management/users/external_id/customer", expected: "/user_management/users/external_id/{id}"},
+		{path: "/connections/conn_123/org_123", expected: "/connections/{id}/{id}"},
+		{path: "", expected: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			require.Equal(t, test.expected, routeTemplate(test.path))
+		})
+	}
+}
+
+func TestDoRequestEditors(t *testing.T) {
+	var calls int
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		header = r.Header.Get("X-Proxy-Authorization")
+	}))
+	defer server.Close()
+
+	editors := []func(*http.Request) error{
+		func(r *http.Request) error {
+			r.Header.Set("X-Proxy-Authorization", "proxy_token")
+			return nil
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	res, err := Do(server.Client(), nil, editors, req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, "proxy_token", header)
+
+	editors = append(editors, func(r *http.Request) error {
+		return errors.New("no proxy token")
+	})
+
+	req, err = http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = Do(server.Client(), nil, editors, req)
+	require.EqualError(t, err, "no proxy token")
+	require.Equal(t, 1, calls)
+}
+
+func TestDoMissingAPIKey(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer ")
+
+	_, err = Do(server.Client(), nil, nil, req)
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+	require.Zero(t, calls)
+
+	editors := []func(*http.Request) error{
+		func(r *http.Request) error {
+			r.Header.Set("Authorization", "Bearer sk_from_vault")
+			return nil
+		},
+	}
+
+	res, err := Do(server.Client(), nil, editors, req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, 1, calls)
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	client := NewHTTPClient(10 * time.Second)
+	require.Equal(t, 10*time.Second, client.Timeout)
+	require.NotNil(t, client.Transport)
+	require.NotEqual(t, http.DefaultTransport, client.Transport)
+	require.NotEqual(t, NewHTTPClient(time.Second).Transport, client.Transport)
+}
+
+type closeRecordingTransport struct {
+	http.RoundTripper
+	closed int
+}
+
+func (t *closeRecordingTransport) CloseIdleConnections() {
+	t.closed++
+}
+
+func TestCloseIdleConnections(t *testing.T) {
+	owned := &closeRecordingTransport{}
+	client := &http.Client{Transport: owned}
+
+	CloseIdleConnections(client, owned)
+	require.Equal(t, 1, owned.closed)
+
+	CloseIdleConnections(client, nil)
+	CloseIdleConnections(nil, owned)
+	require.Equal(t, 1, owned.closed)
+
+	client.Transport = &closeRecordingTransport{}
+	CloseIdleConnections(client, owned)
+	require.Equal(t, 1, owned.closed)
+	require.Zero(t, client.Transport.(*closeRecordingTransport).closed)
+}
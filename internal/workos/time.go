@@ -0,0 +1,21 @@
+package workos
+
+import (
+	"bytes"
+	"time"
+)
+
+// NullableTime decodes an RFC 3339 timestamp, treating null and empty strings
+// as the zero time.
+type NullableTime struct {
+	time.Time
+}
+
+// UnmarshalJSON satisfies the json.Unmarshaler interface.
+func (t *NullableTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) || bytes.Equal(data, []byte(`""`)) {
+		t.Time = time.Time{}
+		return nil
+	}
+	return t.Time.UnmarshalJSON(data)
+}
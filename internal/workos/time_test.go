@@ -0,0 +1,50 @@
+package workos
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullableTime(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     string
+		expected time.Time
+		err      bool
+	}{
+		{
+			scenario: "valid timestamp",
+			data:     `"2021-06-25T19:07:33.155Z"`,
+			expected: time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+		},
+		{
+			scenario: "empty string",
+			data:     `""`,
+		},
+		{
+			scenario: "null",
+			data:     `null`,
+		},
+		{
+			scenario: "invalid timestamp",
+			data:     `"yesterday"`,
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var v NullableTime
+			err := json.Unmarshal([]byte(test.data), &v)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, test.expected.Equal(v.Time))
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package workos
 
+import "github.com/workos/workos-go/v4/pkg/common"
+
 const (
 	// Version represents the SDK version number.
-	Version = "v4.4.0"
+	Version = common.Version
 )
@@ -0,0 +1,18 @@
+package auditlogs
+
+import "fmt"
+
+// validateAction returns an error when the Client declares its Actions and
+// the given action is not one of them.
+func (c *Client) validateAction(action string) error {
+	if len(c.Actions) == 0 {
+		return nil
+	}
+
+	for _, name := range c.Actions {
+		if name == action {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid event action %q: not declared in Actions", action)
+}
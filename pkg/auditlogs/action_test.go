@@ -0,0 +1,51 @@
+package auditlogs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientValidateAction(t *testing.T) {
+	client := &Client{}
+	require.NoError(t, client.validateAction("user.loggedin"))
+
+	client.Actions = []string{"user.logged_in", "user.logged_out", "document.updated"}
+	require.NoError(t, client.validateAction("user.logged_in"))
+	require.NoError(t, client.validateAction("user.logged_out"))
+	require.EqualError(t, client.validateAction("user.loggedin"), `invalid event action "user.loggedin": not declared in Actions`)
+}
+
+func TestCreateEventUndeclaredAction(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:         "test",
+		HTTPClient:     server.Client(),
+		EventsEndpoint: server.URL,
+		Actions:        []string{"user.logged_in"},
+	}
+
+	err := client.CreateEvent(context.Background(), CreateEventOpts{
+		OrganizationID: "org_123456",
+		Event:          Event{Action: "user.loggedin"},
+	})
+	require.EqualError(t, err, `invalid event action "user.loggedin": not declared in Actions`)
+	require.Zero(t, atomic.LoadInt32(&calls))
+
+	err = client.CreateEvent(context.Background(), CreateEventOpts{
+		OrganizationID: "org_123456",
+		Event:          Event{Action: "user.logged_in"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,57 @@
+package auditlogs
+
+import (
+	"context"
+	"net/http"
+)
+
+// Auditable is implemented by the types that can be the actor of an event,
+// such as the authenticated user of an application.
+type Auditable interface {
+	AuditLogActor() Actor
+}
+
+// AuditLogActor satisfies the Auditable interface.
+func (a Actor) AuditLogActor() Actor {
+	return a
+}
+
+// NewAuditable returns an Auditable for a user with the given name and id.
+func NewAuditable(name, id string) Auditable {
+	return Actor{ID: id, Name: name, Type: "user"}
+}
+
+// SetActor sets the actor of the event.
+func (e *Event) SetActor(a Auditable) {
+	e.Actor = a.AuditLogActor()
+}
+
+// SetActorFromContext sets the actor of the event from the value stored in ctx
+// under the given key. The value can be an Auditable, an Actor or an *Actor.
+// It reports whether an actor was found.
+func (e *Event) SetActorFromContext(ctx context.Context, key interface{}) bool {
+	switch v := ctx.Value(key).(type) {
+	case *Actor:
+		if v == nil {
+			return false
+		}
+		e.Actor = *v
+	case Auditable:
+		e.Actor = v.AuditLogActor()
+	default:
+		return false
+	}
+	return true
+}
+
+// SetActorFromRequest sets the actor of the event with the Auditable returned
+// by the extractor for the given request. It reports whether an actor was
+// found.
+func (e *Event) SetActorFromRequest(r *http.Request, extractor func(*http.Request) (Auditable, bool)) bool {
+	a, ok := extractor(r)
+	if !ok || a == nil {
+		return false
+	}
+	e.Actor = a.AuditLogActor()
+	return true
+}
@@ -0,0 +1,101 @@
+package auditlogs
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type contextKey string
+
+const userContextKey = contextKey("user")
+
+type testUser struct {
+	ID    string
+	Email string
+}
+
+func (u testUser) AuditLogActor() Actor {
+	return Actor{ID: u.ID, Name: u.Email, Type: "user"}
+}
+
+func TestSetActorFromContext(t *testing.T) {
+	tests := []struct {
+		scenario string
+		value    interface{}
+		expected Actor
+		found    bool
+	}{
+		{
+			scenario: "Auditable",
+			value:    testUser{ID: "user_123", Email: "jonsmith@example.com"},
+			expected: Actor{ID: "user_123", Name: "jonsmith@example.com", Type: "user"},
+			found:    true,
+		},
+		{
+			scenario: "Actor",
+			value:    Actor{ID: "api_key_123", Type: "api_key"},
+			expected: Actor{ID: "api_key_123", Type: "api_key"},
+			found:    true,
+		},
+		{
+			scenario: "Actor pointer",
+			value:    &Actor{ID: "api_key_123", Type: "api_key"},
+			expected: Actor{ID: "api_key_123", Type: "api_key"},
+			found:    true,
+		},
+		{
+			scenario: "missing value",
+		},
+		{
+			scenario: "unsupported value",
+			value:    "user_123",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			ctx := context.Background()
+			if test.value != nil {
+				ctx = context.WithValue(ctx, userContextKey, test.value)
+			}
+
+			var e Event
+			found := e.SetActorFromContext(ctx, userContextKey)
+			require.Equal(t, test.found, found)
+			require.Equal(t, test.expected, e.Actor)
+		})
+	}
+}
+
+func TestSetActorFromRequest(t *testing.T) {
+	extractor := func(r *http.Request) (Auditable, bool) {
+		id := r.Header.Get("X-User-ID")
+		if id == "" {
+			return nil, false
+		}
+		return testUser{ID: id, Email: "jonsmith@example.com"}, true
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	require.NoError(t, err)
+
+	var e Event
+	require.False(t, e.SetActorFromRequest(r, extractor))
+	require.Equal(t, Actor{}, e.Actor)
+
+	r.Header.Set("X-User-ID", "user_123")
+	require.True(t, e.SetActorFromRequest(r, extractor))
+	require.Equal(t, Actor{ID: "user_123", Name: "jonsmith@example.com", Type: "user"}, e.Actor)
+}
+
+func TestSetActor(t *testing.T) {
+	var e Event
+	e.SetActor(NewAuditable("Jane", "user_123"))
+	require.Equal(t, Actor{ID: "user_123", Name: "Jane", Type: "user"}, e.Actor)
+
+	e.SetActor(Actor{ID: "api_key_123", Type: "api_key"})
+	require.Equal(t, Actor{ID: "api_key_123", Type: "api_key"}, e.Actor)
+}
@@ -35,6 +35,7 @@ package auditlogs
 
 import (
 	"context"
+	"io"
 )
 
 var (
@@ -55,6 +56,11 @@ func CreateEvent(ctx context.Context, e CreateEventOpts) error {
 	return DefaultClient.CreateEvent(ctx, e)
 }
 
+// ReplayFromReader sends the events written to a FallbackWriter.
+func ReplayFromReader(ctx context.Context, r io.Reader) (int, error) {
+	return DefaultClient.ReplayFromReader(ctx, r)
+}
+
 // CreateEvent creates the given event.
 func CreateExport(ctx context.Context, e CreateExportOpts) (AuditLogExport, error) {
 	return DefaultClient.CreateExport(ctx, e)
@@ -64,3 +70,8 @@ func CreateExport(ctx context.Context, e CreateExportOpts) (AuditLogExport, erro
 func GetExport(ctx context.Context, e GetExportOpts) (AuditLogExport, error) {
 	return DefaultClient.GetExport(ctx, e)
 }
+
+// ListEvents lists the Audit Log events of an Organization.
+func ListEvents(ctx context.Context, opts ListEventsOpts) (ListEventsResponse, error) {
+	return DefaultClient.ListEvents(ctx, opts)
+}
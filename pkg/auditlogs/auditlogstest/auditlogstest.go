@@ -0,0 +1,70 @@
+// Package auditlogstest provides utilities to test the code publishing audit
+// log events with the auditlogs package.
+package auditlogstest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/workos/workos-go/v4/pkg/auditlogs"
+)
+
+// NewTestClient returns an auditlogs.Client sending its requests to the given
+// test server instead of WorkOS.
+func NewTestClient(server *httptest.Server) *auditlogs.Client {
+	return &auditlogs.Client{
+		APIKey:          "test",
+		HTTPClient:      server.Client(),
+		EventsEndpoint:  server.URL + "/audit_logs/events",
+		ExportsEndpoint: server.URL + "/audit_logs/exports",
+	}
+}
+
+// Recorder is an http.Handler recording the audit log events it receives, to
+// be served by a test server. The zero value is ready to use.
+type Recorder struct {
+	mu     sync.Mutex
+	events []auditlogs.CreateEventOpts
+}
+
+// NewServer starts a test server recording the audit log events sent by a
+// client created with NewTestClient. It must be closed when done.
+func NewServer() (*httptest.Server, *Recorder) {
+	recorder := &Recorder{}
+	return httptest.NewServer(recorder), recorder
+}
+
+// ServeHTTP records the event sent to the audit log events endpoint. Other
+// requests get a 404 Not Found response.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost || req.URL.Path != "/audit_logs/events" {
+		http.NotFound(w, req)
+		return
+	}
+
+	var event auditlogs.CreateEventOpts
+	if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	event.IdempotencyKey = req.Header.Get("Idempotency-Key")
+
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"success":true}`))
+}
+
+// Events returns the events recorded so far, in the order they were received.
+func (r *Recorder) Events() []auditlogs.CreateEventOpts {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]auditlogs.CreateEventOpts, len(r.events))
+	copy(events, r.events)
+	return events
+}
@@ -0,0 +1,68 @@
+package auditlogstest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/workos/workos-go/v4/pkg/auditlogs"
+	"github.com/workos/workos-go/v4/pkg/auditlogs/auditlogstest"
+)
+
+func TestRecorder(t *testing.T) {
+	server, recorder := auditlogstest.NewServer()
+	defer server.Close()
+
+	client := auditlogstest.NewTestClient(server)
+
+	occurredAt := time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC)
+	err := client.CreateEvent(context.Background(), auditlogs.CreateEventOpts{
+		OrganizationID: "org_123456",
+		Event: auditlogs.Event{
+			Action:     "document.updated",
+			OccurredAt: occurredAt,
+			Actor:      auditlogs.Actor{ID: "user_1", Type: "user"},
+			Targets:    []auditlogs.Target{{ID: "document_39127", Type: "document"}},
+			Metadata:   map[string]interface{}{"successful": true},
+		},
+		IdempotencyKey: "key",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []auditlogs.CreateEventOpts{{
+		OrganizationID: "org_123456",
+		Event: auditlogs.Event{
+			Action:     "document.updated",
+			OccurredAt: occurredAt,
+			Actor:      auditlogs.Actor{ID: "user_1", Type: "user"},
+			Targets:    []auditlogs.Target{{ID: "document_39127", Type: "document"}},
+			Metadata:   map[string]interface{}{"successful": true},
+		},
+		IdempotencyKey: "key",
+	}}, recorder.Events())
+}
+
+func Example() {
+	server, recorder := auditlogstest.NewServer()
+	defer server.Close()
+
+	client := auditlogstest.NewTestClient(server)
+
+	err := client.CreateEvent(context.Background(), auditlogs.CreateEventOpts{
+		OrganizationID: "org_123456",
+		Event: auditlogs.Event{
+			Action: "user.signed_in",
+			Actor:  auditlogs.Actor{ID: "user_1", Type: "user"},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	for _, e := range recorder.Events() {
+		fmt.Println(e.OrganizationID, e.Event.Action, e.Event.Actor.ID)
+	}
+	// Output: org_123456 user.signed_in user_1
+}
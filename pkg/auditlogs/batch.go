@@ -0,0 +1,52 @@
+package auditlogs
+
+import (
+	"sort"
+	"strings"
+)
+
+// DedupKey returns the key identifying duplicate events in DedupEvents.
+type DedupKey func(e CreateEventOpts) string
+
+// DefaultDedupKey identifies an event by its organization, actor, action and
+// targets.
+func DefaultDedupKey(e CreateEventOpts) string {
+	parts := []string{
+		e.OrganizationID,
+		e.Event.Actor.Type + ":" + e.Event.Actor.ID,
+		e.Event.Action,
+	}
+	for _, t := range e.Event.Targets {
+		parts = append(parts, t.Type+":"+t.ID)
+	}
+	return strings.Join(parts, "|")
+}
+
+// SortEvents sorts buffered events by OccurredAt before they are sent. Events
+// that occurred at the same time keep their order.
+func SortEvents(events []CreateEventOpts) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Event.OccurredAt.Before(events[j].Event.OccurredAt)
+	})
+}
+
+// DedupEvents returns the buffered events without duplicates, keeping the
+// first event of each key. A nil key uses DefaultDedupKey. The events slice is
+// left untouched.
+func DedupEvents(events []CreateEventOpts, key DedupKey) []CreateEventOpts {
+	if key == nil {
+		key = DefaultDedupKey
+	}
+
+	seen := make(map[string]struct{}, len(events))
+	deduped := make([]CreateEventOpts, 0, len(events))
+	for _, e := range events {
+		k := key(e)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		deduped = append(deduped, e)
+	}
+	return deduped
+}
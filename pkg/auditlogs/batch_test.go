@@ -0,0 +1,62 @@
+package auditlogs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortEvents(t *testing.T) {
+	at := time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC)
+	events := []CreateEventOpts{
+		{Event: Event{Action: "third", OccurredAt: at.Add(2 * time.Second)}},
+		{Event: Event{Action: "first", OccurredAt: at}},
+		{Event: Event{Action: "second.a", OccurredAt: at.Add(time.Second)}},
+		{Event: Event{Action: "second.b", OccurredAt: at.Add(time.Second)}},
+	}
+
+	SortEvents(events)
+
+	var actions []string
+	for _, e := range events {
+		actions = append(actions, e.Event.Action)
+	}
+	require.Equal(t, []string{"first", "second.a", "second.b", "third"}, actions)
+}
+
+func TestDedupEvents(t *testing.T) {
+	at := time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC)
+	event := func(actorID, action, targetID string, occurredAt time.Time) CreateEventOpts {
+		return CreateEventOpts{
+			OrganizationID: "org_123",
+			Event: Event{
+				Action:     action,
+				OccurredAt: occurredAt,
+				Actor:      Actor{ID: actorID, Type: "user"},
+				Targets:    []Target{{ID: targetID, Type: "document"}},
+			},
+		}
+	}
+
+	events := []CreateEventOpts{
+		event("user_1", "document.updated", "doc_1", at),
+		event("user_1", "document.updated", "doc_1", at.Add(time.Millisecond)),
+		event("user_2", "document.updated", "doc_1", at),
+		event("user_1", "document.deleted", "doc_1", at),
+		event("user_1", "document.updated", "doc_2", at),
+	}
+
+	t.Run("default key", func(t *testing.T) {
+		deduped := DedupEvents(events, nil)
+		require.Equal(t, []CreateEventOpts{events[0], events[2], events[3], events[4]}, deduped)
+		require.Len(t, events, 5)
+	})
+
+	t.Run("custom key", func(t *testing.T) {
+		deduped := DedupEvents(events, func(e CreateEventOpts) string {
+			return e.Event.Actor.ID
+		})
+		require.Equal(t, []CreateEventOpts{events[0], events[2]}, deduped)
+	})
+}
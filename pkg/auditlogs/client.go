@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"log"
 	"net/http"
 	"sync"
 	"time"
 
+	"github.com/google/go-querystring/query"
+	"github.com/workos/workos-go/v4/pkg/common"
 	"github.com/workos/workos-go/v4/pkg/workos_errors"
 
 	"github.com/workos/workos-go/v4/internal/workos"
@@ -16,6 +21,10 @@ import (
 // ResponseLimit is the default number of records to limit a response to.
 const ResponseLimit = 10
 
+// Now returns the time used for events created without OccurredAt. It can be
+// replaced in tests to get deterministic timestamps.
+var Now = func() time.Time { return time.Now().UTC() }
+
 // Order represents the order of records.
 type Order string
 
@@ -35,6 +44,13 @@ type Client struct {
 	// to http.Client.
 	HTTPClient *http.Client
 
+	// The observer notified after every request sent to WorkOS. Optional.
+	Observer common.Observer
+
+	// The functions applied to every request before it is sent, e.g. to add
+	// the headers required by a proxy. An error aborts the request. Optional.
+	RequestEditors []func(*http.Request) error
+
 	// The endpoint used to request WorkOS AuditLog events creation endpoint.
 	// Defaults to https://api.workos.com/audit_logs/events.
 	EventsEndpoint string
@@ -46,7 +62,36 @@ type Client struct {
 	// The function used to encode in JSON. Defaults to json.Marshal.
 	JSONEncode func(v interface{}) ([]byte, error)
 
-	once sync.Once
+	// The maximum number of properties of each metadata of an event. Defaults
+	// to DefaultMaxMetadataProperties and cannot exceed
+	// MaxMetadataPropertiesLimit.
+	MaxMetadataProperties int
+
+	// The action names events may use, such as "user.signed_in". Events with
+	// another action are rejected before being sent. Any action is allowed
+	// when empty.
+	Actions []string
+
+	// When true, CreateEvent validates and encodes events, then logs them
+	// instead of sending them to WorkOS.
+	DryRun bool
+
+	// The Redactor applied to the events logged in dry run mode. Defaults to
+	// common.DefaultRedactor().
+	Redactor *common.Redactor
+
+	// The logger used to log the events in dry run mode. Defaults to the
+	// standard logger.
+	Logger *log.Logger
+
+	// The writer receiving the events that could not be sent because WorkOS
+	// was unreachable or failed, one JSON record per line, so they can be
+	// sent later with ReplayFromReader. Optional.
+	FallbackWriter io.Writer
+
+	ownedTransport http.RoundTripper
+	fallbackMu     sync.Mutex
+	once           sync.Once
 }
 
 // CreateEventOpts represents arguments to create an Audit Logs event.
@@ -82,7 +127,8 @@ type Event struct {
 	// Attributes of event context
 	Context Context `json:"context"`
 
-	// Event metadata.
+	// Event metadata. Values must be primitives, time.Time or flat slices of
+	// primitives.
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
@@ -143,6 +189,70 @@ type CreateExportOpts struct {
 	Targets []string `json:"targets,omitempty"`
 }
 
+// ListEventsOpts contains the options to list the Audit Log events of an
+// Organization. Empty filters are omitted.
+type ListEventsOpts struct {
+	// Organization identifier
+	OrganizationID string `url:"organization_id"`
+
+	// Optional group to filter by
+	Group string `url:"group,omitempty"`
+
+	// Optional actor id to filter by
+	ActorID string `url:"actor_id,omitempty"`
+
+	// Optional target id to filter by
+	TargetID string `url:"target_id,omitempty"`
+
+	// Optional action to filter by
+	Action string `url:"action,omitempty"`
+
+	// Optional ISO-8601 start datetime of the date range filter
+	RangeStart string `url:"range_start,omitempty"`
+
+	// Optional ISO-8601 end datetime of the date range filter
+	RangeEnd string `url:"range_end,omitempty"`
+
+	// Maximum number of records to return.
+	Limit int `url:"limit"`
+
+	// The order in which to paginate records.
+	Order Order `url:"order,omitempty"`
+
+	// Pagination cursor to receive records before a provided event ID.
+	Before string `url:"before,omitempty"`
+
+	// Pagination cursor to receive records after a provided event ID.
+	After string `url:"after,omitempty"`
+}
+
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListEventsOpts) NextPage(m common.ListMetadata) ListEventsOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListEventsOpts) PrevPage(m common.ListMetadata) ListEventsOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
+// ListEventsResponse describes the response structure when listing Audit Log
+// events.
+type ListEventsResponse struct {
+	// List of stored events.
+	Data []Event `json:"data"`
+
+	// Cursor pagination options.
+	ListMetadata common.ListMetadata `json:"listMetadata"`
+}
+
 // AuditLogExportState represents the active state of an AuditLogExport.
 type AuditLogExportState string
 
@@ -171,10 +281,29 @@ type AuditLogExport struct {
 	URL string `json:"url"`
 
 	// AuditLogExport's created at date
-	CreatedAt string `json:"created_at"`
+	CreatedAt time.Time `json:"created_at"`
 
 	// AuditLogExport's updated at date
-	UpdatedAt string `json:"updated_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UnmarshalJSON decodes the AuditLogExport, treating null and empty timestamps as the
+// zero time.
+func (a *AuditLogExport) UnmarshalJSON(data []byte) error {
+	type auditLogExport AuditLogExport
+	var v struct {
+		auditLogExport
+		CreatedAt workos.NullableTime `json:"created_at"`
+		UpdatedAt workos.NullableTime `json:"updated_at"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*a = AuditLogExport(v.auditLogExport)
+	a.CreatedAt = v.CreatedAt.Time
+	a.UpdatedAt = v.UpdatedAt.Time
+	return nil
 }
 
 type GetExportOpts struct {
@@ -183,20 +312,28 @@ type GetExportOpts struct {
 
 func (c *Client) init() {
 	if c.HTTPClient == nil {
-		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+		c.HTTPClient = workos.NewHTTPClient(10 * time.Second)
+		c.ownedTransport = c.HTTPClient.Transport
 	}
 
-	if c.EventsEndpoint == "" {
-		c.EventsEndpoint = "https://api.workos.com/audit_logs/events"
-	}
-
-	if c.ExportsEndpoint == "" {
-		c.ExportsEndpoint = "https://api.workos.com/audit_logs/exports"
-	}
+	c.EventsEndpoint = workos.NormalizeEndpoint(c.EventsEndpoint, workos.DefaultEndpoint+"/audit_logs/events")
+	c.ExportsEndpoint = workos.NormalizeEndpoint(c.ExportsEndpoint, workos.DefaultEndpoint+"/audit_logs/exports")
 
 	if c.JSONEncode == nil {
 		c.JSONEncode = json.Marshal
 	}
+
+	if c.Redactor == nil {
+		c.Redactor = common.DefaultRedactor()
+	}
+
+	c.MaxMetadataProperties = clampMaxMetadataProperties(c.MaxMetadataProperties)
+}
+
+// Close closes the idle connections of the transport created by the Client
+// when HTTPClient is not set. It is a no-op for a user-supplied HTTPClient.
+func (c *Client) Close() {
+	workos.CloseIdleConnections(c.HTTPClient, c.ownedTransport)
 }
 
 // CreateEvent creates an Audit Log event.
@@ -205,11 +342,41 @@ func (c *Client) CreateEvent(ctx context.Context, e CreateEventOpts) error {
 
 	e.Event.OccurredAt = defaultTime(e.Event.OccurredAt)
 
+	if err := c.validateAction(e.Event.Action); err != nil {
+		return err
+	}
+
+	if err := validateEventMetadata(e.Event, c.MaxMetadataProperties); err != nil {
+		return err
+	}
+
 	data, err := c.JSONEncode(e)
 	if err != nil {
 		return err
 	}
 
+	if c.DryRun {
+		c.logf("workos: audit log event not sent (dry run): %s", c.Redactor.RedactBody(data))
+		return nil
+	}
+
+	err = c.postEvent(ctx, data, e.IdempotencyKey)
+	if err != nil && shouldFallback(err) {
+		err = c.writeFallback(data, e.IdempotencyKey, err)
+	}
+	return err
+}
+
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.Logger == nil {
+		log.Printf(format, v...)
+		return
+	}
+	c.Logger.Printf(format, v...)
+}
+
+// postEvent sends the given encoded event.
+func (c *Client) postEvent(ctx context.Context, data []byte, idempotencyKey string) error {
 	req, err := http.NewRequest(http.MethodPost, c.EventsEndpoint, bytes.NewBuffer(data))
 	if err != nil {
 		return err
@@ -217,13 +384,14 @@ func (c *Client) CreateEvent(ctx context.Context, e CreateEventOpts) error {
 	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	if e.IdempotencyKey != "" {
-		req.Header.Set("Idempotency-Key", e.IdempotencyKey)
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
 	}
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
@@ -248,9 +416,10 @@ func (c *Client) CreateExport(ctx context.Context, e CreateExportOpts) (AuditLog
 	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return AuditLogExport{}, err
 	}
@@ -277,9 +446,10 @@ func (c *Client) GetExport(ctx context.Context, e GetExportOpts) (AuditLogExport
 	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return AuditLogExport{}, err
 	}
@@ -295,9 +465,55 @@ func (c *Client) GetExport(ctx context.Context, e GetExportOpts) (AuditLogExport
 	return body, err
 }
 
+// ListEvents lists the Audit Log events of an Organization.
+func (c *Client) ListEvents(ctx context.Context, opts ListEventsOpts) (ListEventsResponse, error) {
+	c.once.Do(c.init)
+
+	if opts.OrganizationID == "" {
+		return ListEventsResponse{}, errors.New("incomplete arguments: missing OrganizationID")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.EventsEndpoint, nil)
+	if err != nil {
+		return ListEventsResponse{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
+
+	if opts.Order == "" {
+		opts.Order = Desc
+	}
+
+	v, err := query.Values(opts)
+	if err != nil {
+		return ListEventsResponse{}, err
+	}
+	req.URL.RawQuery = v.Encode()
+
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
+	if err != nil {
+		return ListEventsResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if err = workos_errors.TryGetHTTPError(res); err != nil {
+		return ListEventsResponse{}, err
+	}
+
+	var body ListEventsResponse
+	dec := json.NewDecoder(res.Body)
+	err = dec.Decode(&body)
+	return body, err
+}
+
 func defaultTime(t time.Time) time.Time {
 	if t == (time.Time{}) {
-		t = time.Now().UTC()
+		t = Now()
 	}
 	return t
 }
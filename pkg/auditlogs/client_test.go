@@ -1,15 +1,20 @@
 package auditlogs
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"github.com/workos/workos-go/v4/pkg/workos_errors"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/workos/workos-go/v4/pkg/common"
 )
 
 var event = CreateEventOpts{
@@ -156,6 +161,144 @@ func TestCreateEvent(t *testing.T) {
 	})
 }
 
+func TestCreateEventIgnoresResponseBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"success":`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:         "test",
+		HTTPClient:     server.Client(),
+		EventsEndpoint: server.URL,
+	}
+
+	err := client.CreateEvent(context.Background(), CreateEventOpts{
+		OrganizationID: "org_123456",
+		Event:          Event{Action: "document.updated"},
+	})
+	require.NoError(t, err)
+}
+
+func TestCreateEventOccurredAt(t *testing.T) {
+	t.Run("OccurredAt is sent as occurred_at", func(t *testing.T) {
+		var payload map[string]map[string]interface{}
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.WriteHeader(http.StatusOK)
+		}
+		server := httptest.NewServer(http.HandlerFunc(handlerFunc))
+		defer server.Close()
+
+		client := &Client{
+			APIKey:         "test",
+			HTTPClient:     server.Client(),
+			EventsEndpoint: server.URL,
+		}
+
+		occurredAt := time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC)
+		err := client.CreateEvent(context.TODO(), CreateEventOpts{
+			OrganizationID: "org_123456",
+			Event: Event{
+				Action:     "document.updated",
+				OccurredAt: occurredAt,
+			},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, "2023-03-14T10:00:00Z", payload["event"]["occurred_at"])
+		require.NotContains(t, payload["event"], "occured_at")
+	})
+
+	t.Run("OccurredAt defaults to now", func(t *testing.T) {
+		var payload CreateEventOpts
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&payload)
+			w.WriteHeader(http.StatusOK)
+		}
+		server := httptest.NewServer(http.HandlerFunc(handlerFunc))
+		defer server.Close()
+
+		client := &Client{
+			APIKey:         "test",
+			HTTPClient:     server.Client(),
+			EventsEndpoint: server.URL,
+		}
+
+		now := time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC)
+		defer func(f func() time.Time) { Now = f }(Now)
+		Now = func() time.Time { return now }
+
+		err := client.CreateEvent(context.TODO(), CreateEventOpts{})
+		require.NoError(t, err)
+		require.Equal(t, now, payload.Event.OccurredAt)
+	})
+}
+
+func TestCreateEventDryRun(t *testing.T) {
+	handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request should be sent in dry run mode")
+	}
+	server := httptest.NewServer(http.HandlerFunc(handlerFunc))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := &Client{
+		APIKey:         "test",
+		HTTPClient:     server.Client(),
+		EventsEndpoint: server.URL,
+		DryRun:         true,
+		Logger:         log.New(&logs, "", 0),
+	}
+
+	t.Run("valid event is logged", func(t *testing.T) {
+		err := client.CreateEvent(context.TODO(), event)
+		require.NoError(t, err)
+		require.Contains(t, logs.String(), `"action":"document.updated"`)
+	})
+
+	t.Run("sensitive fields are redacted", func(t *testing.T) {
+		logs.Reset()
+		err := client.CreateEvent(context.TODO(), CreateEventOpts{
+			OrganizationID: "org_123456",
+			Event: Event{
+				Action:   "user.password_changed",
+				Metadata: map[string]interface{}{"password": "hunter2"},
+			},
+		})
+		require.NoError(t, err)
+		require.Contains(t, logs.String(), `"password":"[REDACTED]"`)
+		require.NotContains(t, logs.String(), "hunter2")
+	})
+
+	t.Run("invalid event is still rejected", func(t *testing.T) {
+		err := client.CreateEvent(context.TODO(), CreateEventOpts{
+			Event: Event{
+				Metadata: map[string]interface{}{"nested": map[string]interface{}{}},
+			},
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("standard logger is used without a logger", func(t *testing.T) {
+		var std bytes.Buffer
+		log.SetOutput(&std)
+		defer log.SetOutput(os.Stderr)
+
+		client := &Client{
+			APIKey:         "test",
+			HTTPClient:     server.Client(),
+			EventsEndpoint: server.URL,
+			DryRun:         true,
+		}
+		err := client.CreateEvent(context.TODO(), event)
+		require.NoError(t, err)
+		require.Contains(t, std.String(), `"action":"document.updated"`)
+	})
+}
+
 func TestCreateExports(t *testing.T) {
 	t.Run("Call succeeds", func(t *testing.T) {
 		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
@@ -280,6 +423,183 @@ func TestGetExports(t *testing.T) {
 	})
 }
 
+func TestListEvents(t *testing.T) {
+	t.Run("Filters are encoded and events are decoded", func(t *testing.T) {
+		var rawQuery string
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			rawQuery = r.URL.RawQuery
+			w.Write([]byte(`{
+				"data": [
+					{
+						"action": "document.updated",
+						"version": 1,
+						"occurred_at": "2023-03-14T10:00:00Z",
+						"actor": {"id": "user_1", "name": "Jon Smith", "type": "user"},
+						"targets": [{"id": "document_39127", "type": "document"}],
+						"context": {"location": "192.0.0.8", "user_agent": "Firefox"},
+						"metadata": {"successful": true}
+					}
+				],
+				"listMetadata": {"before": "", "after": "event_2"}
+			}`))
+		}
+		server := httptest.NewServer(http.HandlerFunc(handlerFunc))
+		defer server.Close()
+
+		client := &Client{
+			APIKey:         "test",
+			HTTPClient:     server.Client(),
+			EventsEndpoint: server.URL,
+		}
+
+		body, err := client.ListEvents(context.TODO(), ListEventsOpts{
+			OrganizationID: "org_123",
+			Group:          "documents",
+			ActorID:        "user_1",
+			Action:         "document.updated",
+			RangeStart:     "2023-03-01T00:00:00Z",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "action=document.updated&actor_id=user_1&group=documents&limit=10&order=desc&organization_id=org_123&range_start=2023-03-01T00%3A00%3A00Z", rawQuery)
+		require.Equal(t, ListEventsResponse{
+			Data: []Event{
+				{
+					Action:     "document.updated",
+					Version:    1,
+					OccurredAt: time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC),
+					Actor:      Actor{ID: "user_1", Name: "Jon Smith", Type: "user"},
+					Targets:    []Target{{ID: "document_39127", Type: "document"}},
+					Context:    Context{Location: "192.0.0.8", UserAgent: "Firefox"},
+					Metadata:   map[string]interface{}{"successful": true},
+				},
+			},
+			ListMetadata: common.ListMetadata{After: "event_2"},
+		}, body)
+	})
+
+	t.Run("Missing organization returns an error", func(t *testing.T) {
+		client := &Client{APIKey: "test"}
+
+		_, err := client.ListEvents(context.TODO(), ListEventsOpts{})
+		require.Error(t, err)
+	})
+
+	t.Run("401 requests returns an error", func(t *testing.T) {
+		handlerFunc := func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+		server := httptest.NewServer(http.HandlerFunc(handlerFunc))
+		defer server.Close()
+
+		DefaultClient = &Client{
+			HTTPClient:     server.Client(),
+			EventsEndpoint: server.URL,
+		}
+		SetAPIKey("test")
+
+		_, err := ListEvents(context.TODO(), ListEventsOpts{OrganizationID: "org_123"})
+		require.Error(t, err)
+	})
+}
+
 type defaultTestHandler struct {
 	header *http.Header
 }
+
+func TestAuditLogExportUnmarshalJSONTimestamps(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     string
+		expected AuditLogExport
+	}{
+		{
+			scenario: "valid timestamps are decoded",
+			data:     `{"id":"audit_log_export_123","created_at":"2021-06-25T19:07:33.155Z","updated_at":"2021-06-26T19:07:33.155Z"}`,
+			expected: AuditLogExport{
+				ID:        "audit_log_export_123",
+				CreatedAt: time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt: time.Date(2021, time.June, 26, 19, 7, 33, 155000000, time.UTC),
+			},
+		},
+		{
+			scenario: "empty timestamps are decoded as the zero time",
+			data:     `{"id":"audit_log_export_123","created_at":"","updated_at":""}`,
+			expected: AuditLogExport{ID: "audit_log_export_123"},
+		},
+		{
+			scenario: "null timestamps are decoded as the zero time",
+			data:     `{"id":"audit_log_export_123","created_at":null,"updated_at":null}`,
+			expected: AuditLogExport{ID: "audit_log_export_123"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var v AuditLogExport
+			err := json.Unmarshal([]byte(test.data), &v)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestClientMissingAPIKey(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient:      server.Client(),
+		EventsEndpoint:  server.URL,
+		ExportsEndpoint: server.URL,
+	}
+
+	err := client.CreateEvent(context.Background(), CreateEventOpts{
+		OrganizationID: "org_123456",
+		Event:          Event{Action: "document.updated"},
+	})
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+
+	_, err = client.CreateExport(context.Background(), CreateExportOpts{OrganizationID: "org_123456"})
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+
+	require.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestClientEndpointTrailingSlash(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:         "test",
+		HTTPClient:     server.Client(),
+		EventsEndpoint: server.URL + "/audit_logs/events/",
+	}
+
+	err := client.CreateEvent(context.Background(), CreateEventOpts{
+		OrganizationID: "org_123",
+		Event:          Event{Action: "document.updated"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/audit_logs/events", path)
+}
+
+func TestListEventsOptsPages(t *testing.T) {
+	opts := ListEventsOpts{OrganizationID: "org_123", Limit: 5, Order: Asc, After: "event_1"}
+
+	next := opts.NextPage(common.ListMetadata{Before: "event_2", After: "event_6"})
+	expected := opts
+	expected.After = "event_6"
+	require.Equal(t, expected, next)
+
+	prev := next.PrevPage(common.ListMetadata{Before: "event_7", After: "event_11"})
+	expected.Before = "event_7"
+	expected.After = ""
+	require.Equal(t, expected, prev)
+}
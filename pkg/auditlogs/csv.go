@@ -0,0 +1,171 @@
+package auditlogs
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvCoreHeader contains the columns written for every event, before the
+// metadata columns.
+var csvCoreHeader = []string{
+	"action",
+	"version",
+	"occurred_at",
+	"actor_id",
+	"actor_name",
+	"actor_type",
+	"targets",
+	"location",
+	"user_agent",
+}
+
+// CSVWriter writes audit log events as CSV rows with a fixed set of metadata
+// columns, which makes it usable to stream events.
+type CSVWriter struct {
+	w             *csv.Writer
+	metadataKeys  []string
+	actorKeys     []string
+	headerWritten bool
+}
+
+// NewCSVWriter returns a CSVWriter writing to w. The metadata and actor
+// metadata columns are given by metadataKeys and actorMetadataKeys and are
+// sorted. Metadata keys of an event that are not listed are not written.
+func NewCSVWriter(w io.Writer, metadataKeys, actorMetadataKeys []string) *CSVWriter {
+	return &CSVWriter{
+		w:            csv.NewWriter(w),
+		metadataKeys: sortedCopy(metadataKeys),
+		actorKeys:    sortedCopy(actorMetadataKeys),
+	}
+}
+
+// Header returns the header row of the CSV.
+func (cw *CSVWriter) Header() []string {
+	header := make([]string, 0, len(csvCoreHeader)+len(cw.metadataKeys)+len(cw.actorKeys))
+	header = append(header, csvCoreHeader...)
+	for _, k := range cw.metadataKeys {
+		header = append(header, "metadata."+k)
+	}
+	for _, k := range cw.actorKeys {
+		header = append(header, "actor.metadata."+k)
+	}
+	return header
+}
+
+// Write writes the given event, preceded by the header row on the first call.
+func (cw *CSVWriter) Write(e Event) error {
+	if !cw.headerWritten {
+		if err := cw.w.Write(cw.Header()); err != nil {
+			return err
+		}
+		cw.headerWritten = true
+	}
+
+	targets := make([]string, 0, len(e.Targets))
+	for _, t := range e.Targets {
+		targets = append(targets, t.Type+":"+t.ID)
+	}
+
+	row := []string{
+		e.Action,
+		strconv.Itoa(e.Version),
+		formatCSVValue(e.OccurredAt),
+		e.Actor.ID,
+		e.Actor.Name,
+		e.Actor.Type,
+		strings.Join(targets, ";"),
+		e.Context.Location,
+		e.Context.UserAgent,
+	}
+	for _, k := range cw.metadataKeys {
+		row = append(row, formatCSVValue(e.Metadata[k]))
+	}
+	for _, k := range cw.actorKeys {
+		row = append(row, formatCSVValue(e.Actor.Metadata[k]))
+	}
+
+	return cw.w.Write(row)
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (cw *CSVWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// WriteCSV writes the given events as CSV. The metadata columns are the union
+// of the metadata keys of all the events, sorted so the output is
+// deterministic.
+func WriteCSV(w io.Writer, events []Event) error {
+	metadataKeys := map[string]struct{}{}
+	actorKeys := map[string]struct{}{}
+	for _, e := range events {
+		for k := range e.Metadata {
+			metadataKeys[k] = struct{}{}
+		}
+		for k := range e.Actor.Metadata {
+			actorKeys[k] = struct{}{}
+		}
+	}
+
+	cw := NewCSVWriter(w, keys(metadataKeys), keys(actorKeys))
+	for _, e := range events {
+		if err := cw.Write(e); err != nil {
+			return err
+		}
+	}
+
+	if len(events) == 0 {
+		if err := cw.w.Write(cw.Header()); err != nil {
+			return err
+		}
+	}
+
+	return cw.Flush()
+}
+
+func formatCSVValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case string:
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		values := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, formatCSVValue(rv.Index(i).Interface()))
+		}
+		return strings.Join(values, ";")
+	}
+
+	return fmt.Sprint(v)
+}
+
+func keys(m map[string]struct{}) []string {
+	k := make([]string, 0, len(m))
+	for key := range m {
+		k = append(k, key)
+	}
+	return k
+}
+
+func sortedCopy(s []string) []string {
+	c := make([]string, len(s))
+	copy(c, s)
+	sort.Strings(c)
+	return c
+}
@@ -0,0 +1,79 @@
+package auditlogs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteCSV(t *testing.T) {
+	occurredAt := time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{
+			Action:     "document.updated",
+			Version:    1,
+			OccurredAt: occurredAt,
+			Actor: Actor{
+				ID:       "user_1",
+				Name:     "Jon Smith",
+				Type:     "user",
+				Metadata: map[string]interface{}{"role": "admin"},
+			},
+			Targets: []Target{
+				{ID: "document_39127", Type: "document"},
+				{ID: "team_123", Type: "team"},
+			},
+			Context: Context{Location: "192.0.0.8", UserAgent: "Firefox"},
+			Metadata: map[string]interface{}{
+				"successful": true,
+				"tags":       []string{"a", "b"},
+			},
+		},
+		{
+			Action:     "user.signed_in",
+			OccurredAt: occurredAt,
+			Actor:      Actor{ID: "user_2", Type: "user"},
+			Metadata: map[string]interface{}{
+				"attempts": 2,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, events)
+	require.NoError(t, err)
+
+	expected := "action,version,occurred_at,actor_id,actor_name,actor_type,targets,location,user_agent,metadata.attempts,metadata.successful,metadata.tags,actor.metadata.role\n" +
+		"document.updated,1,2023-03-14T10:00:00Z,user_1,Jon Smith,user,document:document_39127;team:team_123,192.0.0.8,Firefox,,true,a;b,admin\n" +
+		"user.signed_in,0,2023-03-14T10:00:00Z,user_2,,user,,,,2,,,\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteCSVWithoutEvents(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, nil)
+	require.NoError(t, err)
+	require.Equal(t, "action,version,occurred_at,actor_id,actor_name,actor_type,targets,location,user_agent\n", buf.String())
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	cw := NewCSVWriter(&buf, []string{"successful"}, nil)
+
+	for i := 0; i < 2; i++ {
+		err := cw.Write(Event{
+			Action:   "document.updated",
+			Metadata: map[string]interface{}{"successful": true, "ignored": 1},
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, cw.Flush())
+
+	expected := "action,version,occurred_at,actor_id,actor_name,actor_type,targets,location,user_agent,metadata.successful\n" +
+		"document.updated,0,,,,,,,,true\n" +
+		"document.updated,0,,,,,,,,true\n"
+	require.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,86 @@
+package auditlogs
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/workos/workos-go/v4/pkg/workos_errors"
+)
+
+// fallbackRecord is a line written to the FallbackWriter of a Client.
+type fallbackRecord struct {
+	IdempotencyKey string          `json:"idempotency_key,omitempty"`
+	Request        json.RawMessage `json:"request"`
+}
+
+// shouldFallback reports whether an event that failed to be sent with the
+// given error may succeed later. Events rejected by WorkOS, for example
+// because they are invalid, would be rejected again and are not kept.
+func shouldFallback(err error) bool {
+	var httpErr workos_errors.HTTPError
+	if !errors.As(err, &httpErr) {
+		return !errors.Is(err, workos_errors.ErrMissingAPIKey)
+	}
+	return httpErr.Code == http.StatusTooManyRequests || httpErr.Code >= 500
+}
+
+// writeFallback writes the encoded event to the FallbackWriter of the Client
+// and returns the error that prevented sending it.
+func (c *Client) writeFallback(data []byte, idempotencyKey string, sendErr error) error {
+	if c.FallbackWriter == nil {
+		return sendErr
+	}
+
+	line, err := json.Marshal(fallbackRecord{
+		IdempotencyKey: idempotencyKey,
+		Request:        data,
+	})
+	if err != nil {
+		return fmt.Errorf("%w (event not written to the fallback writer: %s)", sendErr, err)
+	}
+
+	c.fallbackMu.Lock()
+	defer c.fallbackMu.Unlock()
+
+	if _, err := c.FallbackWriter.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("%w (event not written to the fallback writer: %s)", sendErr, err)
+	}
+	return sendErr
+}
+
+// ReplayFromReader sends the events read from r, as written to a
+// FallbackWriter, in order. It stops at the first event that cannot be sent
+// and returns the number of events sent before it, so the remaining lines can
+// be kept for a later replay. Events failing again are not written to the
+// FallbackWriter.
+func (c *Client) ReplayFromReader(ctx context.Context, r io.Reader) (int, error) {
+	c.once.Do(c.init)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 10*1024*1024)
+
+	sent := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var record fallbackRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return sent, fmt.Errorf("invalid fallback record %d: %w", sent+1, err)
+		}
+
+		if err := c.postEvent(ctx, record.Request, record.IdempotencyKey); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	return sent, scanner.Err()
+}
@@ -0,0 +1,92 @@
+package auditlogs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientFallbackWriter(t *testing.T) {
+	var offline int32 = 1
+	var received []string
+	var idempotencyKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&offline) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var opts CreateEventOpts
+		json.NewDecoder(r.Body).Decode(&opts)
+		received = append(received, opts.Event.Action)
+		idempotencyKeys = append(idempotencyKeys, r.Header.Get("Idempotency-Key"))
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	var fallback bytes.Buffer
+	client := &Client{
+		APIKey:         "test",
+		HTTPClient:     server.Client(),
+		EventsEndpoint: server.URL,
+		FallbackWriter: &fallback,
+	}
+
+	for _, action := range []string{"document.updated", "document.deleted"} {
+		err := client.CreateEvent(context.Background(), CreateEventOpts{
+			OrganizationID: "org_123",
+			Event:          Event{Action: action},
+			IdempotencyKey: "key_" + action,
+		})
+		require.Error(t, err)
+	}
+	require.Equal(t, 2, strings.Count(fallback.String(), "\n"))
+
+	t.Run("events are replayed once WorkOS is reachable", func(t *testing.T) {
+		atomic.StoreInt32(&offline, 0)
+
+		sent, err := client.ReplayFromReader(context.Background(), bytes.NewReader(fallback.Bytes()))
+		require.NoError(t, err)
+		require.Equal(t, 2, sent)
+		require.Equal(t, []string{"document.updated", "document.deleted"}, received)
+		require.Equal(t, []string{"key_document.updated", "key_document.deleted"}, idempotencyKeys)
+	})
+
+	t.Run("replay stops at the first event that cannot be sent", func(t *testing.T) {
+		received = nil
+		replayer := &Client{
+			APIKey:         "invalid",
+			HTTPClient:     server.Client(),
+			EventsEndpoint: server.URL,
+		}
+
+		sent, err := replayer.ReplayFromReader(context.Background(), bytes.NewReader(fallback.Bytes()))
+		require.Error(t, err)
+		require.Equal(t, 0, sent)
+		require.Empty(t, received)
+	})
+
+	t.Run("rejected events are not written", func(t *testing.T) {
+		fallback.Reset()
+		client.APIKey = "invalid"
+		defer func() { client.APIKey = "test" }()
+
+		err := client.CreateEvent(context.Background(), CreateEventOpts{
+			OrganizationID: "org_123",
+			Event:          Event{Action: "document.updated"},
+		})
+		require.Error(t, err)
+		require.Empty(t, fallback.String())
+	})
+}
@@ -0,0 +1,108 @@
+package auditlogs
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// DefaultMaxMetadataProperties is the default maximum number of properties of
+// each metadata of an event.
+const DefaultMaxMetadataProperties = 500
+
+// MaxMetadataPropertiesLimit is the highest maximum number of properties of
+// each metadata of an event. Higher values are clamped to it.
+const MaxMetadataPropertiesLimit = 1000
+
+// clampMaxMetadataProperties returns the given maximum number of metadata
+// properties, DefaultMaxMetadataProperties when it is not positive, or
+// MaxMetadataPropertiesLimit when it exceeds it.
+func clampMaxMetadataProperties(max int) int {
+	if max <= 0 {
+		return DefaultMaxMetadataProperties
+	}
+	if max > MaxMetadataPropertiesLimit {
+		return MaxMetadataPropertiesLimit
+	}
+	return max
+}
+
+// validateEventMetadata ensures the metadata of the event, its actor and its
+// targets have at most maxProperties properties and only hold values that can
+// be queried once stored: primitives, time.Time and flat slices of primitives.
+func validateEventMetadata(e Event, maxProperties int) error {
+	if err := validateMetadata("event", e.Metadata, maxProperties); err != nil {
+		return err
+	}
+
+	if err := validateMetadata("actor", e.Actor.Metadata, maxProperties); err != nil {
+		return err
+	}
+
+	for i, target := range e.Targets {
+		if err := validateMetadata(fmt.Sprintf("targets[%d]", i), target.Metadata, maxProperties); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateMetadata(scope string, m map[string]interface{}, maxProperties int) error {
+	if len(m) > maxProperties {
+		return fmt.Errorf("invalid %s metadata: %d properties exceed the limit of %d", scope, len(m), maxProperties)
+	}
+
+	for k, v := range m {
+		if err := validateMetadataValue(v); err != nil {
+			return fmt.Errorf("invalid %s metadata %q: %w", scope, k, err)
+		}
+	}
+	return nil
+}
+
+func validateMetadataValue(v interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	if _, ok := v.(time.Time); ok {
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if isPrimitive(rv.Kind()) {
+		return nil
+	}
+
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		for i := 0; i < rv.Len(); i++ {
+			elem := rv.Index(i)
+			if elem.Kind() == reflect.Interface {
+				if elem.IsNil() {
+					continue
+				}
+				elem = elem.Elem()
+			}
+
+			if !isPrimitive(elem.Kind()) {
+				return fmt.Errorf("slices may only contain primitive values, got %s", elem.Type())
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported value type %T: only primitives, time.Time and flat slices of primitives are allowed", v)
+}
+
+func isPrimitive(k reflect.Kind) bool {
+	switch k {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
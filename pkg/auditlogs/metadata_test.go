@@ -0,0 +1,123 @@
+package auditlogs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMetadataValue(t *testing.T) {
+	tests := []struct {
+		scenario string
+		value    interface{}
+		err      bool
+	}{
+		{scenario: "string", value: "value"},
+		{scenario: "bool", value: true},
+		{scenario: "int", value: 42},
+		{scenario: "uint8", value: uint8(4)},
+		{scenario: "float", value: 4.2},
+		{scenario: "nil", value: nil},
+		{scenario: "time", value: time.Now()},
+		{scenario: "slice of strings", value: []string{"a", "b"}},
+		{scenario: "slice of mixed primitives", value: []interface{}{"a", 1, true, nil}},
+		{scenario: "nested map", value: map[string]interface{}{"a": 1}, err: true},
+		{scenario: "struct", value: struct{ Name string }{"a"}, err: true},
+		{scenario: "pointer", value: &struct{}{}, err: true},
+		{scenario: "nested slice", value: [][]string{{"a"}}, err: true},
+		{scenario: "slice of maps", value: []interface{}{map[string]string{}}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			err := validateMetadataValue(test.value)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateEventMetadata(t *testing.T) {
+	err := validateEventMetadata(Event{
+		Metadata: map[string]interface{}{"successful": true},
+		Actor:    Actor{Metadata: map[string]interface{}{"email": "person@workos.com"}},
+		Targets:  []Target{{Metadata: map[string]interface{}{"size": 12}}},
+	}, DefaultMaxMetadataProperties)
+	require.NoError(t, err)
+
+	err = validateEventMetadata(Event{
+		Targets: []Target{{}, {Metadata: map[string]interface{}{"nested": map[string]string{}}}},
+	}, DefaultMaxMetadataProperties)
+	require.EqualError(t, err, `invalid targets[1] metadata "nested": unsupported value type map[string]string: only primitives, time.Time and flat slices of primitives are allowed`)
+}
+
+func TestCreateEventMaxMetadataProperties(t *testing.T) {
+	tests := []struct {
+		scenario   string
+		max        int
+		properties int
+		err        string
+	}{
+		{
+			scenario:   "default limit",
+			properties: 600,
+			err:        "invalid event metadata: 600 properties exceed the limit of 500",
+		},
+		{
+			scenario:   "lower limit",
+			max:        100,
+			properties: 600,
+			err:        "invalid event metadata: 600 properties exceed the limit of 100",
+		},
+		{
+			scenario:   "higher limit",
+			max:        1000,
+			properties: 600,
+		},
+		{
+			scenario:   "limit above the ceiling",
+			max:        5000,
+			properties: 1200,
+			err:        "invalid event metadata: 1200 properties exceed the limit of 1000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := &Client{
+				APIKey:                "test",
+				HTTPClient:            server.Client(),
+				EventsEndpoint:        server.URL,
+				MaxMetadataProperties: test.max,
+			}
+
+			metadata := map[string]interface{}{}
+			for i := 0; i < test.properties; i++ {
+				metadata[fmt.Sprintf("key_%d", i)] = i
+			}
+
+			err := client.CreateEvent(context.Background(), CreateEventOpts{
+				OrganizationID: "org_123456",
+				Event:          Event{Action: "document.updated", Metadata: metadata},
+			})
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package common
+
+import "context"
+
+// CorrelationIDHeader is the header used to forward a correlation ID to WorkOS.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+type contextKey string
+
+// CorrelationIDContextKey is the context key under which the correlation ID
+// forwarded by the WorkOS clients is stored.
+const CorrelationIDContextKey = contextKey("workos-correlation-id")
+
+// WithCorrelationID returns a copy of ctx carrying the given correlation ID.
+// Requests made by the WorkOS clients with this context forward it in the
+// X-Correlation-ID header.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, CorrelationIDContextKey, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, if any.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(CorrelationIDContextKey).(string)
+	return id
+}
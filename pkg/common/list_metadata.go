@@ -1,5 +1,11 @@
 package common
 
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
 // ListMetadata contains pagination options for WorkOS records.
 type ListMetadata struct {
 	// Pagination cursor to receive records before a provided ID.
@@ -8,3 +14,64 @@ type ListMetadata struct {
 	// Pagination cursor to receive records after a provided ID.
 	After string `json:"after"`
 }
+
+// MaxLimit is the maximum number of records returned by a WorkOS list
+// endpoint.
+const MaxLimit = 100
+
+// ClampLimit returns the number of records to request to a list endpoint:
+// defaultLimit when limit is not positive, and MaxLimit when limit exceeds it.
+func ClampLimit(limit, defaultLimit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	if limit > MaxLimit {
+		return MaxLimit
+	}
+	return limit
+}
+
+// MaxCursorLength is the maximum length of a pagination cursor accepted by
+// ValidateCursor. WorkOS IDs are much shorter; the limit only catches values
+// that cannot be a cursor.
+const MaxCursorLength = 256
+
+// CursorError is returned when a pagination cursor cannot be a WorkOS ID.
+type CursorError struct {
+	// The name of the invalid parameter, such as "after".
+	Param string
+
+	// The invalid cursor.
+	Cursor string
+
+	// Why the cursor is invalid.
+	Reason string
+}
+
+func (e CursorError) Error() string {
+	return fmt.Sprintf("invalid %s cursor %q: %s", e.Param, e.Cursor, e.Reason)
+}
+
+// ValidateCursor checks that a pagination cursor is either empty or looks
+// like a WorkOS ID. The check is lenient so it does not depend on the format
+// of IDs: it only rejects blank cursors, cursors that are too long and cursors
+// containing control characters, which usually come from copy-paste mistakes.
+func ValidateCursor(param, cursor string) error {
+	if cursor == "" {
+		return nil
+	}
+
+	reason := ""
+	switch {
+	case strings.TrimSpace(cursor) == "":
+		reason = "cursor is blank"
+	case len(cursor) > MaxCursorLength:
+		reason = fmt.Sprintf("cursor exceeds %d characters", MaxCursorLength)
+	case strings.IndexFunc(cursor, unicode.IsControl) >= 0:
+		reason = "cursor contains control characters"
+	default:
+		return nil
+	}
+
+	return CursorError{Param: param, Cursor: cursor, Reason: reason}
+}
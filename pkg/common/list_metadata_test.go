@@ -0,0 +1,43 @@
+package common
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClampLimit(t *testing.T) {
+	require.Equal(t, 10, ClampLimit(0, 10))
+	require.Equal(t, 10, ClampLimit(-1, 10))
+	require.Equal(t, 1, ClampLimit(1, 10))
+	require.Equal(t, MaxLimit, ClampLimit(MaxLimit, 10))
+	require.Equal(t, MaxLimit, ClampLimit(MaxLimit+1, 10))
+}
+
+func TestValidateCursor(t *testing.T) {
+	tests := []struct {
+		scenario string
+		cursor   string
+		err      string
+	}{
+		{scenario: "empty cursor"},
+		{scenario: "WorkOS ID", cursor: "user_01E3JC5F5Z1YJNPGVYWV9SX6GH"},
+		{scenario: "unknown ID format", cursor: "01E3JC5F5Z1YJNPGVYWV9SX6GH"},
+		{scenario: "blank cursor", cursor: "  ", err: `invalid after cursor "  ": cursor is blank`},
+		{scenario: "too long cursor", cursor: strings.Repeat("a", MaxCursorLength+1), err: "cursor exceeds 256 characters"},
+		{scenario: "pasted newline", cursor: "user_123\n", err: `invalid after cursor "user_123\n": cursor contains control characters`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			err := ValidateCursor("after", test.cursor)
+			if test.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.IsType(t, CursorError{}, err)
+			require.Contains(t, err.Error(), test.err)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package common
+
+import "time"
+
+// Observer is notified after every request sent to WorkOS by the clients. It
+// can be used to record request counts and latencies.
+type Observer interface {
+	// ObserveRequest is called with the route template of the requested
+	// endpoint, e.g. "/user_management/users/{id}", the status code of the
+	// response and the duration of the request. The status is 0 when no
+	// response was received.
+	ObserveRequest(endpoint string, status int, dur time.Duration)
+}
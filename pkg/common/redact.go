@@ -0,0 +1,74 @@
+package common
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Redacted is the value replacing sensitive data.
+const Redacted = "[REDACTED]"
+
+// Redactor removes sensitive data from requests before they are logged.
+type Redactor struct {
+	// The names of the JSON body fields to redact, at any depth.
+	Fields []string
+}
+
+// DefaultRedactor returns a Redactor for the sensitive data sent to WorkOS.
+// Its fields can be extended with application specific names.
+func DefaultRedactor() *Redactor {
+	return &Redactor{
+		Fields: []string{
+			"access_token",
+			"client_secret",
+			"password",
+			"refresh_token",
+		},
+	}
+}
+
+// RedactBody returns a copy of the JSON body with the values of the sensitive
+// fields replaced. Bodies that are not JSON are entirely redacted.
+func (r *Redactor) RedactBody(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return []byte(Redacted)
+	}
+
+	redacted, err := json.Marshal(r.redactValue(v))
+	if err != nil {
+		return []byte(Redacted)
+	}
+	return redacted
+}
+
+func (r *Redactor) redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if contains(r.Fields, k) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = r.redactValue(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = r.redactValue(value)
+		}
+	}
+	return v
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactorRedactBody(t *testing.T) {
+	tests := []struct {
+		scenario string
+		redactor *Redactor
+		body     string
+		expected string
+	}{
+		{
+			scenario: "sensitive fields are redacted at any depth",
+			redactor: DefaultRedactor(),
+			body:     `{"email":"marcelina@foo-corp.com","password":"hunter2","session":{"access_token":"at","refresh_token":"rt"}}`,
+			expected: `{"email":"marcelina@foo-corp.com","password":"[REDACTED]","session":{"access_token":"[REDACTED]","refresh_token":"[REDACTED]"}}`,
+		},
+		{
+			scenario: "fields in arrays are redacted",
+			redactor: DefaultRedactor(),
+			body:     `[{"client_secret":"sk_test"}]`,
+			expected: `[{"client_secret":"[REDACTED]"}]`,
+		},
+		{
+			scenario: "custom fields are redacted",
+			redactor: &Redactor{Fields: append(DefaultRedactor().Fields, "ssn")},
+			body:     `{"ssn":"078-05-1120","password":"hunter2"}`,
+			expected: `{"password":"[REDACTED]","ssn":"[REDACTED]"}`,
+		},
+		{
+			scenario: "bodies that are not JSON are redacted",
+			redactor: DefaultRedactor(),
+			body:     `password=hunter2`,
+			expected: `[REDACTED]`,
+		},
+		{
+			scenario: "empty bodies are left untouched",
+			redactor: DefaultRedactor(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			require.Equal(t, test.expected, string(test.redactor.RedactBody([]byte(test.body))))
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package common
+
+import "sync"
+
+// Version is the version of the WorkOS Go SDK.
+const Version = "v4.4.0"
+
+var (
+	appInfoMu sync.RWMutex
+	appInfo   string
+)
+
+// SetAppInfo sets the name and version of the application using the SDK.
+// They are appended to the User-Agent header of the requests sent to WorkOS,
+// which helps support identify the application. An empty name removes them.
+func SetAppInfo(name, version string) {
+	appInfoMu.Lock()
+	defer appInfoMu.Unlock()
+
+	switch {
+	case name == "":
+		appInfo = ""
+	case version == "":
+		appInfo = name
+	default:
+		appInfo = name + "/" + version
+	}
+}
+
+// UserAgent returns the User-Agent header sent to WorkOS: the SDK version
+// followed by the application set with SetAppInfo, if any.
+func UserAgent() string {
+	appInfoMu.RLock()
+	defer appInfoMu.RUnlock()
+
+	if appInfo == "" {
+		return "workos-go/" + Version
+	}
+	return "workos-go/" + Version + " " + appInfo
+}
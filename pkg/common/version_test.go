@@ -0,0 +1,22 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserAgent(t *testing.T) {
+	defer SetAppInfo("", "")
+
+	require.Equal(t, "workos-go/"+Version, UserAgent())
+
+	SetAppInfo("foo-corp", "1.2.3")
+	require.Equal(t, "workos-go/"+Version+" foo-corp/1.2.3", UserAgent())
+
+	SetAppInfo("foo-corp", "")
+	require.Equal(t, "workos-go/"+Version+" foo-corp", UserAgent())
+
+	SetAppInfo("", "")
+	require.Equal(t, "workos-go/"+Version, UserAgent())
+}
@@ -36,20 +36,33 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The observer notified after every request sent to WorkOS. Optional.
+	Observer common.Observer
+
+	// The functions applied to every request before it is sent, e.g. to add
+	// the headers required by a proxy. An error aborts the request. Optional.
+	RequestEditors []func(*http.Request) error
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
-	once sync.Once
+	ownedTransport http.RoundTripper
+	once           sync.Once
 }
 
 func (c *Client) init() {
 	if c.HTTPClient == nil {
-		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+		c.HTTPClient = workos.NewHTTPClient(10 * time.Second)
+		c.ownedTransport = c.HTTPClient.Transport
 	}
 
-	if c.Endpoint == "" {
-		c.Endpoint = "https://api.workos.com"
-	}
+	c.Endpoint = workos.NormalizeEndpoint(c.Endpoint, workos.DefaultEndpoint)
+}
+
+// Close closes the idle connections of the transport created by the Client
+// when HTTPClient is not set. It is a no-op for a user-supplied HTTPClient.
+func (c *Client) Close() {
+	workos.CloseIdleConnections(c.HTTPClient, c.ownedTransport)
 }
 
 // UserEmail contains data about a Directory User's e-mail address.
@@ -154,6 +167,23 @@ type ListUsersOpts struct {
 	After string `url:"after,omitempty"`
 }
 
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListUsersOpts) NextPage(m common.ListMetadata) ListUsersOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListUsersOpts) PrevPage(m common.ListMetadata) ListUsersOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
 // ListUsersResponse describes the response structure when requesting
 // provisioned Directory Users.
 type ListUsersResponse struct {
@@ -184,10 +214,9 @@ func (c *Client) ListUsers(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	if opts.Order == "" {
 		opts.Order = Desc
@@ -199,7 +228,7 @@ func (c *Client) ListUsers(
 	}
 
 	req.URL.RawQuery = v.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return ListUsersResponse{}, err
 	}
@@ -263,6 +292,23 @@ type ListGroupsOpts struct {
 	After string `url:"after,omitempty"`
 }
 
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListGroupsOpts) NextPage(m common.ListMetadata) ListGroupsOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListGroupsOpts) PrevPage(m common.ListMetadata) ListGroupsOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
 // ListGroupsResponse describes the response structure when requesting
 // provisioned Directory Groups.
 type ListGroupsResponse struct {
@@ -293,11 +339,10 @@ func (c *Client) ListGroups(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	if opts.Order == "" {
 		opts.Order = Desc
@@ -309,7 +354,7 @@ func (c *Client) ListGroups(
 	}
 
 	req.URL.RawQuery = v.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return ListGroupsResponse{}, err
 	}
@@ -355,9 +400,10 @@ func (c *Client) GetUser(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return User{}, err
 	}
@@ -403,9 +449,10 @@ func (c *Client) GetGroup(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return Group{}, err
 	}
@@ -514,6 +561,23 @@ type ListDirectoriesOpts struct {
 	After string `url:"after,omitempty"`
 }
 
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListDirectoriesOpts) NextPage(m common.ListMetadata) ListDirectoriesOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListDirectoriesOpts) PrevPage(m common.ListMetadata) ListDirectoriesOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
 // ListDirectoriesResponse describes the response structure when requesting
 // existing Directories.
 type ListDirectoriesResponse struct {
@@ -544,10 +608,9 @@ func (c *Client) ListDirectories(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	if opts.Order == "" {
 		opts.Order = Desc
@@ -559,7 +622,7 @@ func (c *Client) ListDirectories(
 	}
 
 	req.URL.RawQuery = v.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return ListDirectoriesResponse{}, err
 	}
@@ -604,9 +667,10 @@ func (c *Client) GetDirectory(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return Directory{}, err
 	}
@@ -652,9 +716,10 @@ func (c *Client) DeleteDirectory(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
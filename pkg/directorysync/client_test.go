@@ -3,6 +3,7 @@ package directorysync
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/workos/workos-go/v4/pkg/common"
+	"github.com/workos/workos-go/v4/pkg/workos_errors"
 )
 
 func TestListUsers(t *testing.T) {
@@ -585,3 +587,44 @@ func deleteDirectoryTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 
 }
+
+func TestClientNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	_, err := client.GetDirectory(context.Background(), GetDirectoryOpts{Directory: "directory_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+
+	_, err = client.GetUser(context.Background(), GetUserOpts{User: "directory_user_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+
+	_, err = client.GetGroup(context.Background(), GetGroupOpts{Group: "directory_group_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+
+	err = client.DeleteDirectory(context.Background(), DeleteDirectoryOpts{Directory: "directory_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+}
+
+func TestListUsersOptsPages(t *testing.T) {
+	opts := ListUsersOpts{Directory: "directory_123", Limit: 5, Order: Asc, After: "directory_user_1"}
+
+	next := opts.NextPage(common.ListMetadata{Before: "directory_user_2", After: "directory_user_6"})
+	expected := opts
+	expected.After = "directory_user_6"
+	require.Equal(t, expected, next)
+
+	prev := next.PrevPage(common.ListMetadata{Before: "directory_user_7", After: "directory_user_11"})
+	expected.Before = "directory_user_7"
+	expected.After = ""
+	require.Equal(t, expected, prev)
+}
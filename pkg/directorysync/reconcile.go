@@ -0,0 +1,101 @@
+package directorysync
+
+import "encoding/json"
+
+// EventAction represents the change described by a Directory event.
+type EventAction string
+
+// Constants that enumerate the actions of Directory events.
+const (
+	Created     EventAction = "created"
+	Updated     EventAction = "updated"
+	Deleted     EventAction = "deleted"
+	UserAdded   EventAction = "user_added"
+	UserRemoved EventAction = "user_removed"
+)
+
+// UserEvent is a change of a Directory User.
+type UserEvent struct {
+	// The change applied to the User.
+	Action EventAction
+
+	// The User, as of the event.
+	User User
+}
+
+// GroupEvent is a change of a Directory Group or of its members.
+type GroupEvent struct {
+	// The change applied to the Group.
+	Action EventAction
+
+	// The Group, as of the event.
+	Group Group
+
+	// The User added to or removed from the Group. It is only set for the
+	// UserAdded and UserRemoved actions.
+	User *User
+}
+
+// Reconciler applies Directory events to a local copy of a Directory. Events
+// may be delivered more than once, so they should be applied idempotently.
+type Reconciler interface {
+	// ApplyUserEvent applies a change of a Directory User.
+	ApplyUserEvent(ev UserEvent) error
+
+	// ApplyGroupEvent applies a change of a Directory Group.
+	ApplyGroupEvent(ev GroupEvent) error
+}
+
+var userEventActions = map[string]EventAction{
+	"dsync.user.created": Created,
+	"dsync.user.updated": Updated,
+	"dsync.user.deleted": Deleted,
+}
+
+var groupEventActions = map[string]EventAction{
+	"dsync.group.created":      Created,
+	"dsync.group.updated":      Updated,
+	"dsync.group.deleted":      Deleted,
+	"dsync.group.user_added":   UserAdded,
+	"dsync.group.user_removed": UserRemoved,
+}
+
+// Dispatch decodes the data of the event of the given type, as received from
+// the Events API or a webhook, and routes it to the Reconciler. It returns
+// false with a nil error for events that are not about Directory Users or
+// Groups.
+func Dispatch(r Reconciler, eventType string, data json.RawMessage) (bool, error) {
+	if action, ok := userEventActions[eventType]; ok {
+		var user User
+		if err := json.Unmarshal(data, &user); err != nil {
+			return true, err
+		}
+		return true, r.ApplyUserEvent(UserEvent{Action: action, User: user})
+	}
+
+	action, ok := groupEventActions[eventType]
+	if !ok {
+		return false, nil
+	}
+
+	if action == UserAdded || action == UserRemoved {
+		var membership struct {
+			User  User  `json:"user"`
+			Group Group `json:"group"`
+		}
+		if err := json.Unmarshal(data, &membership); err != nil {
+			return true, err
+		}
+		return true, r.ApplyGroupEvent(GroupEvent{
+			Action: action,
+			Group:  membership.Group,
+			User:   &membership.User,
+		})
+	}
+
+	var group Group
+	if err := json.Unmarshal(data, &group); err != nil {
+		return true, err
+	}
+	return true, r.ApplyGroupEvent(GroupEvent{Action: action, Group: group})
+}
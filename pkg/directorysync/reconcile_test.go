@@ -0,0 +1,92 @@
+package directorysync
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingReconciler struct {
+	userEvents  []UserEvent
+	groupEvents []GroupEvent
+	err         error
+}
+
+func (r *recordingReconciler) ApplyUserEvent(ev UserEvent) error {
+	r.userEvents = append(r.userEvents, ev)
+	return r.err
+}
+
+func (r *recordingReconciler) ApplyGroupEvent(ev GroupEvent) error {
+	r.groupEvents = append(r.groupEvents, ev)
+	return r.err
+}
+
+func TestDispatch(t *testing.T) {
+	userData := json.RawMessage(`{"id":"directory_user_123","directory_id":"directory_123","first_name":"Rick","state":"active"}`)
+	groupData := json.RawMessage(`{"id":"directory_group_123","directory_id":"directory_123","name":"Developers"}`)
+
+	t.Run("user events are routed to ApplyUserEvent", func(t *testing.T) {
+		r := &recordingReconciler{}
+
+		for _, eventType := range []string{"dsync.user.created", "dsync.user.updated", "dsync.user.deleted"} {
+			handled, err := Dispatch(r, eventType, userData)
+			require.NoError(t, err)
+			require.True(t, handled)
+		}
+
+		user := User{ID: "directory_user_123", DirectoryID: "directory_123", FirstName: "Rick", State: Active}
+		require.Equal(t, []UserEvent{
+			{Action: Created, User: user},
+			{Action: Updated, User: user},
+			{Action: Deleted, User: user},
+		}, r.userEvents)
+		require.Empty(t, r.groupEvents)
+	})
+
+	t.Run("group events are routed to ApplyGroupEvent", func(t *testing.T) {
+		r := &recordingReconciler{}
+
+		handled, err := Dispatch(r, "dsync.group.deleted", groupData)
+		require.NoError(t, err)
+		require.True(t, handled)
+
+		handled, err = Dispatch(r, "dsync.group.user_added", json.RawMessage(`{"user":`+string(userData)+`,"group":`+string(groupData)+`}`))
+		require.NoError(t, err)
+		require.True(t, handled)
+
+		group := Group{ID: "directory_group_123", DirectoryID: "directory_123", Name: "Developers"}
+		require.Equal(t, []GroupEvent{
+			{Action: Deleted, Group: group},
+			{
+				Action: UserAdded,
+				Group:  group,
+				User:   &User{ID: "directory_user_123", DirectoryID: "directory_123", FirstName: "Rick", State: Active},
+			},
+		}, r.groupEvents)
+		require.Empty(t, r.userEvents)
+	})
+
+	t.Run("other events are ignored", func(t *testing.T) {
+		r := &recordingReconciler{}
+
+		handled, err := Dispatch(r, "connection.activated", json.RawMessage(`{}`))
+		require.NoError(t, err)
+		require.False(t, handled)
+		require.Empty(t, r.userEvents)
+		require.Empty(t, r.groupEvents)
+	})
+
+	t.Run("errors are returned", func(t *testing.T) {
+		r := &recordingReconciler{err: errors.New("database unavailable")}
+
+		handled, err := Dispatch(r, "dsync.user.created", userData)
+		require.True(t, handled)
+		require.EqualError(t, err, "database unavailable")
+
+		_, err = Dispatch(r, "dsync.user.created", json.RawMessage(`not json`))
+		require.Error(t, err)
+	})
+}
@@ -55,20 +55,33 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The observer notified after every request sent to WorkOS. Optional.
+	Observer common.Observer
+
+	// The functions applied to every request before it is sent, e.g. to add
+	// the headers required by a proxy. An error aborts the request. Optional.
+	RequestEditors []func(*http.Request) error
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
-	once sync.Once
+	ownedTransport http.RoundTripper
+	once           sync.Once
 }
 
 func (c *Client) init() {
 	if c.HTTPClient == nil {
-		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+		c.HTTPClient = workos.NewHTTPClient(10 * time.Second)
+		c.ownedTransport = c.HTTPClient.Transport
 	}
 
-	if c.Endpoint == "" {
-		c.Endpoint = "https://api.workos.com"
-	}
+	c.Endpoint = workos.NormalizeEndpoint(c.Endpoint, workos.DefaultEndpoint)
+}
+
+// Close closes the idle connections of the transport created by the Client
+// when HTTPClient is not set. It is a no-op for a user-supplied HTTPClient.
+func (c *Client) Close() {
+	workos.CloseIdleConnections(c.HTTPClient, c.ownedTransport)
 }
 
 // Event contains data about a particular Event.
@@ -134,10 +147,9 @@ func (c *Client) ListEvents(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	queryValues, err := query.Values(opts)
 	if err != nil {
@@ -145,7 +157,7 @@ func (c *Client) ListEvents(
 	}
 
 	req.URL.RawQuery = queryValues.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return ListEventsResponse{}, err
 	}
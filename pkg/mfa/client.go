@@ -7,10 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 	"sync"
 	"time"
 
+	"github.com/workos/workos-go/v4/pkg/common"
 	"github.com/workos/workos-go/v4/pkg/workos_errors"
 
 	"github.com/workos/workos-go/v4/internal/workos"
@@ -33,23 +33,29 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The observer notified after every request sent to WorkOS. Optional.
+	Observer common.Observer
+
+	// The functions applied to every request before it is sent, e.g. to add
+	// the headers required by a proxy. An error aborts the request. Optional.
+	RequestEditors []func(*http.Request) error
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
 	// The function used to encode in JSON. Defaults to json.Marshal.
 	JSONEncode func(v interface{}) ([]byte, error)
 
-	once sync.Once
+	ownedTransport http.RoundTripper
+	once           sync.Once
 }
 
 func (c *Client) init() {
-	if c.Endpoint == "" {
-		c.Endpoint = "https://api.workos.com"
-	}
-	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+	c.Endpoint = workos.NormalizeEndpoint(c.Endpoint, workos.DefaultEndpoint)
 
 	if c.HTTPClient == nil {
-		c.HTTPClient = &http.Client{Timeout: time.Second * 15}
+		c.HTTPClient = workos.NewHTTPClient(time.Second * 15)
+		c.ownedTransport = c.HTTPClient.Transport
 	}
 
 	if c.JSONEncode == nil {
@@ -57,6 +63,12 @@ func (c *Client) init() {
 	}
 }
 
+// Close closes the idle connections of the transport created by the Client
+// when HTTPClient is not set. It is a no-op for a user-supplied HTTPClient.
+func (c *Client) Close() {
+	workos.CloseIdleConnections(c.HTTPClient, c.ownedTransport)
+}
+
 // Type represents the type of Authentication Factor
 type FactorType string
 
@@ -216,8 +228,9 @@ func (c *Client) EnrollFactor(
 	}
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	resp, err := c.HTTPClient.Do(req)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+	resp, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return Factor{}, err
 	}
@@ -256,9 +269,10 @@ func (c *Client) ChallengeFactor(
 	}
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return Challenge{}, err
 	}
@@ -312,8 +326,9 @@ func (c *Client) VerifyChallenge(
 	}
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	resp, err := c.HTTPClient.Do(req)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+	resp, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return VerifyChallengeResponse{}, err
 	}
@@ -356,9 +371,10 @@ func (c *Client) DeleteFactor(
 
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
@@ -381,9 +397,10 @@ func (c *Client) GetFactor(
 	}
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return Factor{}, err
 	}
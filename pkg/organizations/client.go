@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -19,6 +20,10 @@ import (
 // ResponseLimit is the default number of records to limit a response to.
 const ResponseLimit = 10
 
+// DefaultConcurrency is the default number of Organizations requested
+// concurrently by GetOrganizationsByIDs.
+const DefaultConcurrency = 5
+
 // Order represents the order of records.
 type Order string
 
@@ -37,29 +42,64 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The observer notified after every request sent to WorkOS. Optional.
+	Observer common.Observer
+
+	// The functions applied to every request before it is sent, e.g. to add
+	// the headers required by a proxy. An error aborts the request. Optional.
+	RequestEditors []func(*http.Request) error
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
 	// The function used to encode in JSON. Defaults to json.Marshal.
 	JSONEncode func(v interface{}) ([]byte, error)
 
-	once sync.Once
+	ownedTransport http.RoundTripper
+	once           sync.Once
 }
 
 func (c *Client) init() {
 	if c.HTTPClient == nil {
-		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+		c.HTTPClient = workos.NewHTTPClient(10 * time.Second)
+		c.ownedTransport = c.HTTPClient.Transport
 	}
 
-	if c.Endpoint == "" {
-		c.Endpoint = "https://api.workos.com"
-	}
+	c.Endpoint = workos.NormalizeEndpoint(c.Endpoint, workos.DefaultEndpoint)
 
 	if c.JSONEncode == nil {
 		c.JSONEncode = json.Marshal
 	}
 }
 
+// Close closes the idle connections of the transport created by the Client
+// when HTTPClient is not set. It is a no-op for a user-supplied HTTPClient.
+func (c *Client) Close() {
+	workos.CloseIdleConnections(c.HTTPClient, c.ownedTransport)
+}
+
+// OrganizationDomainState represents the verification state of an
+// Organization Domain.
+type OrganizationDomainState string
+
+// Constants that enumerate the verification states of an Organization Domain.
+const (
+	OrganizationDomainPending  OrganizationDomainState = "pending"
+	OrganizationDomainVerified OrganizationDomainState = "verified"
+	OrganizationDomainFailed   OrganizationDomainState = "failed"
+)
+
+// OrganizationDomainVerificationStrategy represents how an Organization
+// Domain is verified.
+type OrganizationDomainVerificationStrategy string
+
+// Constants that enumerate the verification strategies of an Organization
+// Domain.
+const (
+	OrganizationDomainDNS    OrganizationDomainVerificationStrategy = "dns"
+	OrganizationDomainManual OrganizationDomainVerificationStrategy = "manual"
+)
+
 // OrganizationDomain contains data about an Organization's Domains.
 type OrganizationDomain struct {
 	// The Organization Domain's unique identifier.
@@ -67,6 +107,24 @@ type OrganizationDomain struct {
 
 	// The domain value
 	Domain string `json:"domain"`
+
+	// The verification state of the domain.
+	State OrganizationDomainState `json:"state"`
+
+	// How the domain is verified.
+	VerificationStrategy OrganizationDomainVerificationStrategy `json:"verification_strategy"`
+}
+
+// OrganizationDomainData contains the data of a domain to add to an
+// Organization.
+type OrganizationDomainData struct {
+	// The domain value.
+	Domain string `json:"domain"`
+
+	// The verification state of the domain. Use OrganizationDomainVerified
+	// for domains that are already trusted and OrganizationDomainPending to
+	// verify them through DNS.
+	State OrganizationDomainState `json:"state"`
 }
 
 // Organization contains data about a WorkOS Organization.
@@ -85,10 +143,32 @@ type Organization struct {
 	Domains []OrganizationDomain `json:"domains"`
 
 	// The timestamp of when the Organization was created.
-	CreatedAt string `json:"created_at"`
+	CreatedAt time.Time `json:"created_at"`
 
 	// The timestamp of when the Organization was updated.
-	UpdatedAt string `json:"updated_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Custom key-value pairs attached to the Organization.
+	Metadata map[string]string `json:"metadata"`
+}
+
+// UnmarshalJSON decodes the Organization, treating null and empty timestamps as the
+// zero time.
+func (o *Organization) UnmarshalJSON(data []byte) error {
+	type organization Organization
+	var v struct {
+		organization
+		CreatedAt workos.NullableTime `json:"created_at"`
+		UpdatedAt workos.NullableTime `json:"updated_at"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*o = Organization(v.organization)
+	o.CreatedAt = v.CreatedAt.Time
+	o.UpdatedAt = v.UpdatedAt.Time
+	return nil
 }
 
 // GetOrganizationOpts contains the options to request details for an Organization.
@@ -99,7 +179,8 @@ type GetOrganizationOpts struct {
 
 // ListOrganizationsOpts contains the options to request Organizations.
 type ListOrganizationsOpts struct {
-	// Domains of the Organization.
+	// Domains of the Organization. Organizations matching any of the given
+	// domains are returned.
 	Domains []string `url:"domains,brackets,omitempty"`
 
 	// Maximum number of records to return.
@@ -115,6 +196,23 @@ type ListOrganizationsOpts struct {
 	After string `url:"after,omitempty"`
 }
 
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListOrganizationsOpts) NextPage(m common.ListMetadata) ListOrganizationsOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListOrganizationsOpts) PrevPage(m common.ListMetadata) ListOrganizationsOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
 // ListOrganizationsResponse describes the response structure when requesting
 // Organizations
 type ListOrganizationsResponse struct {
@@ -135,8 +233,16 @@ type CreateOrganizationOpts struct {
 	AllowProfilesOutsideOrganization bool `json:"allow_profiles_outside_organization"`
 
 	// Domains of the Organization.
+	//
+	// Deprecated: use DomainData instead.
 	Domains []string `json:"domains"`
 
+	// Domains of the Organization, along with their verification state.
+	DomainData []OrganizationDomainData `json:"domain_data,omitempty"`
+
+	// Custom key-value pairs to attach to the Organization.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
 	// Optional unique identifier to ensure idempotency
 	IdempotencyKey string `json:"idempotency_iey,omitempty"`
 }
@@ -154,7 +260,15 @@ type UpdateOrganizationOpts struct {
 	AllowProfilesOutsideOrganization bool
 
 	// Domains of the Organization.
+	//
+	// Deprecated: use DomainData instead.
 	Domains []string
+
+	// Domains of the Organization, along with their verification state.
+	DomainData []OrganizationDomainData
+
+	// Custom key-value pairs to attach to the Organization.
+	Metadata map[string]string
 }
 
 // GetOrganization gets an Organization.
@@ -181,9 +295,10 @@ func (c *Client) GetOrganization(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return Organization{}, err
 	}
@@ -219,11 +334,10 @@ func (c *Client) ListOrganizations(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	if opts.Order == "" {
 		opts.Order = Desc
@@ -236,7 +350,7 @@ func (c *Client) ListOrganizations(
 
 	req.URL.RawQuery = q.Encode()
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return ListOrganizationsResponse{}, err
 	}
@@ -252,10 +366,110 @@ func (c *Client) ListOrganizations(
 	return body, err
 }
 
+// FindOrganizationByDomainOpts contains the options to find the Organization
+// owning a domain.
+type FindOrganizationByDomainOpts struct {
+	// The domain owned by the Organization (eg. foo-corp.com).
+	Domain string
+}
+
+// FindOrganizationByDomain returns the Organization owning the given domain.
+// It returns false with a nil error when no Organization matches.
+func (c *Client) FindOrganizationByDomain(
+	ctx context.Context,
+	opts FindOrganizationByDomainOpts,
+) (Organization, bool, error) {
+	if opts.Domain == "" {
+		return Organization{}, false, errors.New("incomplete arguments: missing Domain")
+	}
+
+	res, err := c.ListOrganizations(ctx, ListOrganizationsOpts{
+		Domains: []string{opts.Domain},
+		Limit:   1,
+	})
+	if err != nil {
+		return Organization{}, false, err
+	}
+
+	if len(res.Data) == 0 {
+		return Organization{}, false, nil
+	}
+
+	return res.Data[0], true, nil
+}
+
+// GetOrganizationsByIDsOpts contains the options to request several
+// Organizations.
+type GetOrganizationsByIDsOpts struct {
+	// Organizations unique identifiers.
+	Organizations []string
+
+	// The maximum number of Organizations requested concurrently.
+	// Defaults to DefaultConcurrency.
+	Concurrency int
+}
+
+// GetOrganizationResult is the outcome of requesting one Organization.
+type GetOrganizationResult struct {
+	// The ID of the requested Organization.
+	OrganizationID string
+
+	// The Organization, when Err is nil.
+	Organization Organization
+
+	// The error that prevented getting the Organization, if any.
+	Err error
+}
+
+// GetOrganizationsByIDs gets the given Organizations concurrently. The results
+// are in the same order as the requested IDs, each holding its own error so a
+// missing Organization does not fail the others.
+func (c *Client) GetOrganizationsByIDs(
+	ctx context.Context,
+	opts GetOrganizationsByIDsOpts,
+) []GetOrganizationResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	results := make([]GetOrganizationResult, len(opts.Organizations))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range opts.Organizations {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			organization, err := c.GetOrganization(ctx, GetOrganizationOpts{
+				Organization: id,
+			})
+			results[i] = GetOrganizationResult{
+				OrganizationID: id,
+				Organization:   organization,
+				Err:            err,
+			}
+		}(i, id)
+	}
+
+	wg.Wait()
+	return results
+}
+
 // CreateOrganization creates an Organization.
 func (c *Client) CreateOrganization(ctx context.Context, opts CreateOrganizationOpts) (Organization, error) {
 	c.once.Do(c.init)
 
+	if err := workos.ValidateMetadata(opts.Metadata); err != nil {
+		return Organization{}, err
+	}
+
 	data, err := c.JSONEncode(opts)
 	if err != nil {
 		return Organization{}, err
@@ -269,10 +483,11 @@ func (c *Client) CreateOrganization(ctx context.Context, opts CreateOrganization
 	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Idempotency-Key", opts.IdempotencyKey)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return Organization{}, err
 	}
@@ -303,9 +518,19 @@ func (c *Client) UpdateOrganization(ctx context.Context, opts UpdateOrganization
 
 		// Domains of the Organization.
 		Domains []string `json:"domains"`
+
+		// Domains of the Organization, along with their verification state.
+		DomainData []OrganizationDomainData `json:"domain_data,omitempty"`
+
+		// Custom key-value pairs to attach to the Organization.
+		Metadata map[string]string `json:"metadata,omitempty"`
+	}
+
+	if err := workos.ValidateMetadata(opts.Metadata); err != nil {
+		return Organization{}, err
 	}
 
-	update_opts := UpdateOrganizationChangeOpts{opts.Name, opts.AllowProfilesOutsideOrganization, opts.Domains}
+	update_opts := UpdateOrganizationChangeOpts{opts.Name, opts.AllowProfilesOutsideOrganization, opts.Domains, opts.DomainData, opts.Metadata}
 
 	data, err := c.JSONEncode(update_opts)
 	if err != nil {
@@ -320,9 +545,10 @@ func (c *Client) UpdateOrganization(ctx context.Context, opts UpdateOrganization
 	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return Organization{}, err
 	}
@@ -368,9 +594,10 @@ func (c *Client) DeleteOrganization(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
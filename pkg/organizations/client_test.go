@@ -3,13 +3,19 @@ package organizations
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/workos/workos-go/v4/pkg/common"
+	"github.com/workos/workos-go/v4/pkg/workos_errors"
 )
 
 func TestGetOrganization(t *testing.T) {
@@ -202,6 +208,156 @@ func listOrganizationsTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+func TestListOrganizationsDomains(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	_, err := client.ListOrganizations(context.Background(), ListOrganizationsOpts{
+		Domains: []string{"foo-corp.com", "bar-corp.com"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo-corp.com", "bar-corp.com"}, query["domains[]"])
+}
+
+func TestFindOrganizationByDomain(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := ListOrganizationsResponse{Data: []Organization{}}
+		if r.URL.Query().Get("domains[]") == "foo-corp.com" {
+			response.Data = append(response.Data, Organization{
+				ID:   "organization_id",
+				Name: "Foo Corp",
+				Domains: []OrganizationDomain{
+					{ID: "organization_domain_id", Domain: "foo-corp.com"},
+				},
+			})
+		}
+
+		body, _ := json.Marshal(response)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	t.Run("FindOrganizationByDomain returns the matching Organization", func(t *testing.T) {
+		organization, found, err := client.FindOrganizationByDomain(context.Background(), FindOrganizationByDomainOpts{
+			Domain: "foo-corp.com",
+		})
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "organization_id", organization.ID)
+	})
+
+	t.Run("FindOrganizationByDomain returns not found when no Organization matches", func(t *testing.T) {
+		organization, found, err := client.FindOrganizationByDomain(context.Background(), FindOrganizationByDomainOpts{
+			Domain: "unknown.com",
+		})
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Equal(t, Organization{}, organization)
+	})
+
+	t.Run("FindOrganizationByDomain requires a domain", func(t *testing.T) {
+		_, _, err := client.FindOrganizationByDomain(context.Background(), FindOrganizationByDomainOpts{})
+		require.Error(t, err)
+	})
+}
+
+func TestGetOrganizationsByIDs(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			peak := atomic.LoadInt32(&maxInFlight)
+			if n <= peak || atomic.CompareAndSwapInt32(&maxInFlight, peak, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		id := strings.TrimPrefix(r.URL.Path, "/organizations/")
+		if strings.HasPrefix(id, "missing") {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Organization not found"}`))
+			return
+		}
+
+		body, _ := json.Marshal(Organization{ID: id, Name: "Foo Corp"})
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	results := client.GetOrganizationsByIDs(context.Background(), GetOrganizationsByIDsOpts{
+		Organizations: []string{"org_1", "missing_1", "org_2", "org_3", "missing_2"},
+		Concurrency:   2,
+	})
+	require.Len(t, results, 5)
+
+	for i, id := range []string{"org_1", "missing_1", "org_2", "org_3", "missing_2"} {
+		require.Equal(t, id, results[i].OrganizationID)
+		if strings.HasPrefix(id, "missing") {
+			require.Error(t, results[i].Err)
+			require.Equal(t, Organization{}, results[i].Organization)
+			continue
+		}
+		require.NoError(t, results[i].Err)
+		require.Equal(t, id, results[i].Organization.ID)
+	}
+	require.True(t, atomic.LoadInt32(&maxInFlight) <= 2)
+}
+
+func TestListOrganizationsLimit(t *testing.T) {
+	var limit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit = r.URL.Query().Get("limit")
+		w.Write([]byte(`{"data":[],"listMetadata":{}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	tests := []struct {
+		limit    int
+		expected string
+	}{
+		{limit: 0, expected: "10"},
+		{limit: 50, expected: "50"},
+		{limit: 1000, expected: "100"},
+	}
+
+	for _, test := range tests {
+		_, err := client.ListOrganizations(context.Background(), ListOrganizationsOpts{Limit: test.limit})
+		require.NoError(t, err)
+		require.Equal(t, test.expected, limit)
+	}
+}
+
 func TestCreateOrganization(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -336,6 +492,190 @@ func createOrganizationTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+func TestOrganizationMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var opts struct {
+			Name     string            `json:"name"`
+			Metadata map[string]string `json:"metadata"`
+		}
+		json.NewDecoder(r.Body).Decode(&opts)
+
+		body, _ := json.Marshal(Organization{
+			ID:       "organization_id",
+			Name:     opts.Name,
+			Metadata: opts.Metadata,
+		})
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	t.Run("CreateOrganization sends the metadata", func(t *testing.T) {
+		organization, err := client.CreateOrganization(context.Background(), CreateOrganizationOpts{
+			Name:     "Foo Corp",
+			Metadata: map[string]string{"tier": "enterprise"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"tier": "enterprise"}, organization.Metadata)
+	})
+
+	t.Run("UpdateOrganization sends the metadata", func(t *testing.T) {
+		organization, err := client.UpdateOrganization(context.Background(), UpdateOrganizationOpts{
+			Organization: "organization_id",
+			Name:         "Foo Corp",
+			Metadata:     map[string]string{"tier": "free", "region": "eu"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"tier": "free", "region": "eu"}, organization.Metadata)
+	})
+
+	t.Run("metadata exceeding the API limits is rejected", func(t *testing.T) {
+		tooManyKeys := map[string]string{}
+		for i := 0; i <= MaxMetadataKeys; i++ {
+			tooManyKeys[fmt.Sprintf("key_%d", i)] = "value"
+		}
+
+		for _, metadata := range []map[string]string{
+			tooManyKeys,
+			{strings.Repeat("k", MaxMetadataKeyLength+1): "value"},
+			{"key": strings.Repeat("v", MaxMetadataValueLength+1)},
+		} {
+			_, err := client.CreateOrganization(context.Background(), CreateOrganizationOpts{
+				Name:     "Foo Corp",
+				Metadata: metadata,
+			})
+			require.Error(t, err)
+
+			_, err = client.UpdateOrganization(context.Background(), UpdateOrganizationOpts{
+				Organization: "organization_id",
+				Metadata:     metadata,
+			})
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestOrganizationDomainData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var opts struct {
+			Name       string                   `json:"name"`
+			DomainData []OrganizationDomainData `json:"domain_data"`
+		}
+		json.NewDecoder(r.Body).Decode(&opts)
+
+		domains := make([]map[string]string, 0, len(opts.DomainData))
+		for i, d := range opts.DomainData {
+			strategy := "dns"
+			if d.State == OrganizationDomainVerified {
+				strategy = "manual"
+			}
+			domains = append(domains, map[string]string{
+				"id":                    fmt.Sprintf("org_domain_%d", i),
+				"domain":                d.Domain,
+				"state":                 string(d.State),
+				"verification_strategy": strategy,
+			})
+		}
+
+		body, _ := json.Marshal(map[string]interface{}{
+			"id":      "organization_id",
+			"name":    opts.Name,
+			"domains": domains,
+		})
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	domainData := []OrganizationDomainData{
+		{Domain: "foo-corp.com", State: OrganizationDomainVerified},
+		{Domain: "foo-corp.io", State: OrganizationDomainPending},
+	}
+	expected := []OrganizationDomain{
+		{
+			ID:                   "org_domain_0",
+			Domain:               "foo-corp.com",
+			State:                OrganizationDomainVerified,
+			VerificationStrategy: OrganizationDomainManual,
+		},
+		{
+			ID:                   "org_domain_1",
+			Domain:               "foo-corp.io",
+			State:                OrganizationDomainPending,
+			VerificationStrategy: OrganizationDomainDNS,
+		},
+	}
+
+	t.Run("CreateOrganization sends the domain data", func(t *testing.T) {
+		organization, err := client.CreateOrganization(context.Background(), CreateOrganizationOpts{
+			Name:       "Foo Corp",
+			DomainData: domainData,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, organization.Domains)
+	})
+
+	t.Run("UpdateOrganization sends the domain data", func(t *testing.T) {
+		organization, err := client.UpdateOrganization(context.Background(), UpdateOrganizationOpts{
+			Organization: "organization_id",
+			Name:         "Foo Corp",
+			DomainData:   domainData,
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, organization.Domains)
+	})
+}
+
+func TestClientNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	_, err := client.GetOrganization(context.Background(), GetOrganizationOpts{Organization: "org_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+
+	err = client.DeleteOrganization(context.Background(), DeleteOrganizationOpts{Organization: "org_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+}
+
+func TestClientEndpointTrailingSlash(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"id":"org_123"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL + "/",
+		APIKey:     "test",
+	}
+
+	_, err := client.GetOrganization(context.Background(), GetOrganizationOpts{Organization: "org_123"})
+	require.NoError(t, err)
+	require.Equal(t, "/organizations/org_123", path)
+}
+
 func TestUpdateOrganization(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -455,3 +795,54 @@ func updateOrganizationTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(body)
 }
+
+func TestOrganizationUnmarshalJSONTimestamps(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     string
+		expected Organization
+	}{
+		{
+			scenario: "valid timestamps are decoded",
+			data:     `{"id":"org_123","created_at":"2021-06-25T19:07:33.155Z","updated_at":"2021-06-26T19:07:33.155Z"}`,
+			expected: Organization{
+				ID:        "org_123",
+				CreatedAt: time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt: time.Date(2021, time.June, 26, 19, 7, 33, 155000000, time.UTC),
+			},
+		},
+		{
+			scenario: "empty timestamps are decoded as the zero time",
+			data:     `{"id":"org_123","created_at":"","updated_at":""}`,
+			expected: Organization{ID: "org_123"},
+		},
+		{
+			scenario: "null timestamps are decoded as the zero time",
+			data:     `{"id":"org_123","created_at":null,"updated_at":null}`,
+			expected: Organization{ID: "org_123"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var v Organization
+			err := json.Unmarshal([]byte(test.data), &v)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestListOrganizationsOptsPages(t *testing.T) {
+	opts := ListOrganizationsOpts{Domains: []string{"foo-corp.com"}, Limit: 5, Order: Asc, After: "org_1"}
+
+	next := opts.NextPage(common.ListMetadata{Before: "org_2", After: "org_6"})
+	expected := opts
+	expected.After = "org_6"
+	require.Equal(t, expected, next)
+
+	prev := next.PrevPage(common.ListMetadata{Before: "org_7", After: "org_11"})
+	expected.Before = "org_7"
+	expected.After = ""
+	require.Equal(t, expected, prev)
+}
@@ -0,0 +1,10 @@
+package organizations
+
+import "github.com/workos/workos-go/v4/internal/workos"
+
+// Limits of the Organization metadata enforced by the WorkOS API.
+const (
+	MaxMetadataKeys        = workos.MaxMetadataKeys
+	MaxMetadataKeyLength   = workos.MaxMetadataKeyLength
+	MaxMetadataValueLength = workos.MaxMetadataValueLength
+)
@@ -33,6 +33,22 @@ func ListOrganizations(
 	return DefaultClient.ListOrganizations(ctx, opts)
 }
 
+// FindOrganizationByDomain returns the Organization owning a domain.
+func FindOrganizationByDomain(
+	ctx context.Context,
+	opts FindOrganizationByDomainOpts,
+) (Organization, bool, error) {
+	return DefaultClient.FindOrganizationByDomain(ctx, opts)
+}
+
+// GetOrganizationsByIDs gets several Organizations concurrently.
+func GetOrganizationsByIDs(
+	ctx context.Context,
+	opts GetOrganizationsByIDsOpts,
+) []GetOrganizationResult {
+	return DefaultClient.GetOrganizationsByIDs(ctx, opts)
+}
+
 // CreateOrganization creates an Organization.
 func CreateOrganization(
 	ctx context.Context,
@@ -56,3 +72,11 @@ func DeleteOrganization(
 ) error {
 	return DefaultClient.DeleteOrganization(ctx, opts)
 }
+
+// ListRoles gets the Roles available to the members of an Organization.
+func ListRoles(
+	ctx context.Context,
+	opts ListRolesOpts,
+) (ListRolesResponse, error) {
+	return DefaultClient.ListRoles(ctx, opts)
+}
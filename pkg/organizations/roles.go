@@ -0,0 +1,106 @@
+package organizations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/workos/workos-go/v4/internal/workos"
+	"github.com/workos/workos-go/v4/pkg/common"
+	"github.com/workos/workos-go/v4/pkg/workos_errors"
+)
+
+// RoleType represents where a Role is defined.
+type RoleType string
+
+// Constants that enumerate the types of a Role.
+const (
+	// The Role is defined for the whole environment.
+	EnvironmentRole RoleType = "EnvironmentRole"
+
+	// The Role is specific to an Organization.
+	OrganizationRole RoleType = "OrganizationRole"
+)
+
+// Role contains data about a Role that can be granted to the members of an
+// Organization.
+type Role struct {
+	// The Role's unique identifier.
+	ID string `json:"id"`
+
+	// The Role's name.
+	Name string `json:"name"`
+
+	// The Role's slug, used to grant the Role to Organization Memberships.
+	Slug string `json:"slug"`
+
+	// The Role's description.
+	Description string `json:"description"`
+
+	// Whether the Role is defined for the environment or for the
+	// Organization.
+	Type RoleType `json:"type"`
+}
+
+// ListRolesOpts contains the options to list the Roles of an Organization.
+type ListRolesOpts struct {
+	// The ID of the Organization.
+	OrganizationID string
+}
+
+// ListRolesResponse describes the response structure when requesting the
+// Roles of an Organization.
+type ListRolesResponse struct {
+	// The Roles available to the members of the Organization.
+	Data []Role `json:"data"`
+}
+
+// ListRoles gets the Roles available to the members of an Organization,
+// including the environment Roles.
+func (c *Client) ListRoles(
+	ctx context.Context,
+	opts ListRolesOpts,
+) (ListRolesResponse, error) {
+	c.once.Do(c.init)
+
+	if opts.OrganizationID == "" {
+		return ListRolesResponse{}, errors.New("incomplete arguments: missing OrganizationID")
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/organizations/%s/roles",
+		c.Endpoint,
+		opts.OrganizationID,
+	)
+	req, err := http.NewRequest(
+		http.MethodGet,
+		endpoint,
+		nil,
+	)
+	if err != nil {
+		return ListRolesResponse{}, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
+	if err != nil {
+		return ListRolesResponse{}, err
+	}
+	defer res.Body.Close()
+
+	if err = workos_errors.TryGetHTTPError(res); err != nil {
+		return ListRolesResponse{}, err
+	}
+
+	var body ListRolesResponse
+	dec := json.NewDecoder(res.Body)
+	err = dec.Decode(&body)
+	return body, err
+}
@@ -0,0 +1,75 @@
+package organizations
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRoles(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{
+			"object": "list",
+			"data": [
+				{
+					"object": "role",
+					"id": "role_01EHQMYV6MBK39QC5PZXHY59C3",
+					"name": "Admin",
+					"slug": "admin",
+					"description": "Can manage billing and members.",
+					"type": "EnvironmentRole",
+					"created_at": "2021-06-25T19:07:33.155Z",
+					"updated_at": "2021-06-25T19:07:33.155Z"
+				},
+				{
+					"object": "role",
+					"id": "role_01EHQMYV6MBK39QC5PZXHY59C4",
+					"name": "Auditor",
+					"slug": "org-auditor",
+					"description": "",
+					"type": "OrganizationRole",
+					"created_at": "2021-06-25T19:07:33.155Z",
+					"updated_at": "2021-06-25T19:07:33.155Z"
+				}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	res, err := client.ListRoles(context.Background(), ListRolesOpts{OrganizationID: "org_123"})
+	require.NoError(t, err)
+	require.Equal(t, "/organizations/org_123/roles", path)
+	require.Equal(t, []Role{
+		{
+			ID:          "role_01EHQMYV6MBK39QC5PZXHY59C3",
+			Name:        "Admin",
+			Slug:        "admin",
+			Description: "Can manage billing and members.",
+			Type:        EnvironmentRole,
+		},
+		{
+			ID:   "role_01EHQMYV6MBK39QC5PZXHY59C4",
+			Name: "Auditor",
+			Slug: "org-auditor",
+			Type: OrganizationRole,
+		},
+	}, res.Data)
+
+	_, err = client.ListRoles(context.Background(), ListRolesOpts{})
+	require.EqualError(t, err, "incomplete arguments: missing OrganizationID")
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/workos/workos-go/v4/pkg/common"
 	"github.com/workos/workos-go/v4/pkg/workos_errors"
 
 	"github.com/workos/workos-go/v4/internal/workos"
@@ -35,29 +36,42 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The observer notified after every request sent to WorkOS. Optional.
+	Observer common.Observer
+
+	// The functions applied to every request before it is sent, e.g. to add
+	// the headers required by a proxy. An error aborts the request. Optional.
+	RequestEditors []func(*http.Request) error
+
 	// The endpoint to WorkOS API. Defaults to https://api.workos.com.
 	Endpoint string
 
 	// The function used to encode in JSON. Defaults to json.Marshal.
 	JSONEncode func(v interface{}) ([]byte, error)
 
-	once sync.Once
+	ownedTransport http.RoundTripper
+	once           sync.Once
 }
 
 func (c *Client) init() {
 	if c.HTTPClient == nil {
-		c.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+		c.HTTPClient = workos.NewHTTPClient(10 * time.Second)
+		c.ownedTransport = c.HTTPClient.Transport
 	}
 
-	if c.Endpoint == "" {
-		c.Endpoint = "https://api.workos.com"
-	}
+	c.Endpoint = workos.NormalizeEndpoint(c.Endpoint, workos.DefaultEndpoint)
 
 	if c.JSONEncode == nil {
 		c.JSONEncode = json.Marshal
 	}
 }
 
+// Close closes the idle connections of the transport created by the Client
+// when HTTPClient is not set. It is a no-op for a user-supplied HTTPClient.
+func (c *Client) Close() {
+	workos.CloseIdleConnections(c.HTTPClient, c.ownedTransport)
+}
+
 // GenerateLinkIntent represents the intent of an Admin Portal.
 type GenerateLinkIntent string
 
@@ -78,10 +92,10 @@ type GenerateLinkOpts struct {
 	Organization string `json:"organization"`
 
 	// The URL to which WorkOS should send users when they click on the link to return to your website.
-	ReturnURL string `json:"return_url"`
+	ReturnURL string `json:"return_url,omitempty"`
 
 	// The URL to which WorkOS will redirect users to upon successfully setting up Single Sign On or Directory Sync.
-	SuccessURL string `json:"success_url"`
+	SuccessURL string `json:"success_url,omitempty"`
 }
 
 // generatedLinkResponse represents the generated Portal Link
@@ -110,9 +124,10 @@ func (c *Client) GenerateLink(
 	req = req.WithContext(ctx)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.Observer, c.RequestEditors, req)
 	if err != nil {
 		return "", err
 	}
@@ -93,6 +93,56 @@ func TestGenerateLink(t *testing.T) {
 	}
 }
 
+func TestGenerateLinkRequest(t *testing.T) {
+	var method, path string
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		payload = nil
+		json.NewDecoder(r.Body).Decode(&payload)
+		generateLinkTestHandler(w, r)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	t.Run("all options are sent", func(t *testing.T) {
+		link, err := client.GenerateLink(context.Background(), GenerateLinkOpts{
+			Intent:       SSO,
+			Organization: "organization_id",
+			ReturnURL:    "https://foo-corp.app.com/settings",
+			SuccessURL:   "https://foo-corp.app.com/settings/success",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://id.workos.test/portal/launch?secret=1234", link)
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/portal/generate_link", path)
+		require.Equal(t, map[string]interface{}{
+			"intent":       "sso",
+			"organization": "organization_id",
+			"return_url":   "https://foo-corp.app.com/settings",
+			"success_url":  "https://foo-corp.app.com/settings/success",
+		}, payload)
+	})
+
+	t.Run("empty URLs are omitted", func(t *testing.T) {
+		_, err := client.GenerateLink(context.Background(), GenerateLinkOpts{
+			Intent:       DSync,
+			Organization: "organization_id",
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"intent":       "dsync",
+			"organization": "organization_id",
+		}, payload)
+	})
+}
+
 func generateLinkTestHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
 	if auth != "Bearer test" {
@@ -0,0 +1,166 @@
+package retryablehttp
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFailureThreshold is the default number of consecutive failures
+	// after which a CircuitBreaker opens.
+	DefaultFailureThreshold = 5
+
+	// DefaultCooldown is the default duration a CircuitBreaker stays open
+	// before letting a request through to test recovery.
+	DefaultCooldown = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned instead of sending a request while the
+// CircuitBreaker of a RetryTransport is open.
+var ErrCircuitOpen = errors.New("retryablehttp: circuit breaker is open")
+
+// now is the clock used by circuit breakers, overridden in tests.
+var now = time.Now
+
+// CircuitState represents the state of a CircuitBreaker.
+type CircuitState int
+
+// Constants that enumerate the states of a CircuitBreaker.
+const (
+	// Requests are sent normally.
+	CircuitClosed CircuitState = iota
+
+	// Requests fail with ErrCircuitOpen until the cooldown elapses.
+	CircuitOpen
+
+	// A single request is let through to test recovery. Its success closes
+	// the circuit and its failure opens it again.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreaker stops sending requests after a number of consecutive
+// failures, so an outage is not made worse by retries. A failure is a
+// transport error or a response with a 429 or 5xx status code.
+//
+// A CircuitBreaker is safe for concurrent use and is meant to be shared by
+// the requests sent to the same API.
+type CircuitBreaker struct {
+	// The number of consecutive failures after which the circuit opens.
+	//
+	// Defaults to DefaultFailureThreshold.
+	FailureThreshold int
+
+	// How long the circuit stays open before letting a request through.
+	//
+	// Defaults to DefaultCooldown.
+	Cooldown time.Duration
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker returns a CircuitBreaker opening after failureThreshold
+// consecutive failures for the given cooldown.
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && b.cooledDown() {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow returns ErrCircuitOpen when a request must not be sent.
+func (b *CircuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if !b.cooledDown() {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		b.probing = true
+		return nil
+
+	case CircuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// record updates the circuit with the outcome of a request let through by
+// allow.
+func (b *CircuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if !failed {
+		b.state = CircuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.failureThreshold() {
+		b.state = CircuitOpen
+		b.openedAt = now()
+	}
+}
+
+// release lets another request test recovery when a request let through by
+// allow ended without an outcome, such as a cancelled context.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *CircuitBreaker) cooledDown() bool {
+	cooldown := b.Cooldown
+	if cooldown <= 0 {
+		cooldown = DefaultCooldown
+	}
+	return now().Sub(b.openedAt) >= cooldown
+}
+
+func (b *CircuitBreaker) failureThreshold() int {
+	if b.FailureThreshold <= 0 {
+		return DefaultFailureThreshold
+	}
+	return b.FailureThreshold
+}
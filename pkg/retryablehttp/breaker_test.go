@@ -0,0 +1,115 @@
+package retryablehttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	clock := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	var failing int32 = 1
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	breaker := NewCircuitBreaker(2, time.Minute)
+	client := &http.Client{Transport: &RetryTransport{Breaker: breaker}}
+
+	get := func() (int, error) {
+		res, err := client.Get(server.URL)
+		if err != nil {
+			return 0, err
+		}
+		res.Body.Close()
+		return res.StatusCode, nil
+	}
+
+	// Consecutive failures open the circuit.
+	for i := 0; i < 2; i++ {
+		require.Equal(t, CircuitClosed, breaker.State())
+		status, err := get()
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, status)
+	}
+	require.Equal(t, CircuitOpen, breaker.State())
+
+	// Requests are short-circuited while the circuit is open.
+	_, err := get()
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// A failing probe after the cooldown opens the circuit again.
+	clock = clock.Add(time.Minute)
+	require.Equal(t, CircuitHalfOpen, breaker.State())
+	status, err := get()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, status)
+	require.Equal(t, CircuitOpen, breaker.State())
+
+	_, err = get()
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	// A successful probe closes the circuit.
+	atomic.StoreInt32(&failing, 0)
+	clock = clock.Add(time.Minute)
+	status, err = get()
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestCircuitBreakerHalfOpenAllowsSingleProbe(t *testing.T) {
+	defer func(f func() time.Time) { now = f }(now)
+	clock := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+
+	breaker := NewCircuitBreaker(1, time.Minute)
+	require.NoError(t, breaker.allow())
+	breaker.record(true)
+	require.Equal(t, ErrCircuitOpen, breaker.allow())
+
+	clock = clock.Add(time.Minute)
+	require.NoError(t, breaker.allow())
+	require.Equal(t, ErrCircuitOpen, breaker.allow())
+
+	breaker.release()
+	require.NoError(t, breaker.allow())
+	breaker.record(false)
+	require.Equal(t, CircuitClosed, breaker.State())
+}
+
+func TestRetryTransportStopsRetryingWhenCircuitOpens(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &RetryTransport{
+		MaxRetries: 5,
+		MinDelay:   time.Millisecond,
+		Breaker:    NewCircuitBreaker(3, time.Minute),
+	}}
+
+	_, err := client.Get(server.URL)
+	require.True(t, errors.Is(err, ErrCircuitOpen))
+	require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
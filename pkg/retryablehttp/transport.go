@@ -0,0 +1,231 @@
+// Package `retryablehttp` provides an http.RoundTripper that retries failed
+// requests to the WorkOS API.
+package retryablehttp
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMinDelay is the default delay before the first retry.
+	DefaultMinDelay = 500 * time.Millisecond
+
+	// DefaultMaxDelay is the default maximum delay between two attempts.
+	DefaultMaxDelay = 30 * time.Second
+)
+
+// Backoff returns the delay to wait before the given retry attempt, starting at
+// zero. prev is the delay waited before the previous attempt, or zero for the
+// first retry. The returned delay should be between minDelay and maxDelay.
+type Backoff func(attempt int, minDelay, maxDelay, prev time.Duration) time.Duration
+
+// jitter is the random source of DecorrelatedJitter. It is seeded on start
+// since the global math/rand source is not seeded before Go 1.20, which would
+// give every process the same delays.
+var jitter = struct {
+	sync.Mutex
+	rand *rand.Rand
+}{
+	rand: rand.New(rand.NewSource(time.Now().UnixNano())),
+}
+
+// DecorrelatedJitter is a Backoff picking a random delay between minDelay and
+// three times the previous delay, capped at maxDelay. It spreads the retries of
+// concurrent clients so they do not hit the API in lockstep.
+func DecorrelatedJitter(attempt int, minDelay, maxDelay, prev time.Duration) time.Duration {
+	if prev < minDelay {
+		prev = minDelay
+	}
+
+	upper := prev * 3
+	if upper <= 0 || upper > maxDelay {
+		upper = maxDelay
+	}
+	if upper <= minDelay {
+		return minDelay
+	}
+
+	jitter.Lock()
+	defer jitter.Unlock()
+	return minDelay + time.Duration(jitter.rand.Int63n(int64(upper-minDelay)+1))
+}
+
+// Exponential is a Backoff doubling the delay on each attempt, capped at
+// maxDelay, without any jitter.
+func Exponential(attempt int, minDelay, maxDelay, prev time.Duration) time.Duration {
+	backoff := minDelay << uint(attempt)
+	if backoff <= 0 || backoff > maxDelay {
+		return maxDelay
+	}
+	return backoff
+}
+
+// RetryTransport is an http.RoundTripper that retries requests failing with a
+// 429 or 5xx status code, using a jittered backoff by default.
+type RetryTransport struct {
+	// The http.RoundTripper used to send requests.
+	//
+	// Defaults to http.DefaultTransport.
+	Base http.RoundTripper
+
+	// The maximum number of retries of a request. Zero disables retries.
+	MaxRetries int
+
+	// The delay before the first retry. Defaults to DefaultMinDelay.
+	MinDelay time.Duration
+
+	// The maximum delay between two attempts, including delays requested by
+	// a Retry-After header. Defaults to DefaultMaxDelay.
+	MaxDelay time.Duration
+
+	// The function computing the delay between two attempts when the response
+	// has no Retry-After header.
+	//
+	// Defaults to DecorrelatedJitter.
+	Backoff Backoff
+
+	// The optional CircuitBreaker that short-circuits requests with
+	// ErrCircuitOpen while WorkOS keeps failing. Each attempt, including
+	// retries, is checked against and recorded by the breaker.
+	Breaker *CircuitBreaker
+}
+
+// NewRetryTransport returns a RetryTransport wrapping base that retries a
+// request up to maxRetries times. A nil base uses http.DefaultTransport.
+func NewRetryTransport(base http.RoundTripper, maxRetries int) *RetryTransport {
+	return &RetryTransport{
+		Base:       base,
+		MaxRetries: maxRetries,
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := bufferBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev time.Duration
+	for attempt := 0; ; attempt++ {
+		r := req
+		if body != nil {
+			r = req.Clone(req.Context())
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		if t.Breaker != nil {
+			if err := t.Breaker.allow(); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := t.base().RoundTrip(r)
+		if t.Breaker != nil {
+			if err != nil && req.Context().Err() != nil {
+				t.Breaker.release()
+			} else {
+				t.Breaker.record(err != nil || shouldRetry(res))
+			}
+		}
+
+		if err != nil || !shouldRetry(res) || attempt >= t.MaxRetries {
+			return res, err
+		}
+
+		delay := t.delay(attempt, prev, res)
+		prev = delay
+		io.Copy(ioutil.Discard, res.Body)
+		res.Body.Close()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (t *RetryTransport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+func (t *RetryTransport) delay(attempt int, prev time.Duration, res *http.Response) time.Duration {
+	minDelay := t.MinDelay
+	if minDelay <= 0 {
+		minDelay = DefaultMinDelay
+	}
+
+	maxDelay := t.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+
+	if d, ok := retryAfter(res); ok {
+		if d > maxDelay {
+			return maxDelay
+		}
+		return d
+	}
+
+	backoff := t.Backoff
+	if backoff == nil {
+		backoff = DecorrelatedJitter
+	}
+	return backoff(attempt, minDelay, maxDelay, prev)
+}
+
+func shouldRetry(res *http.Response) bool {
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
+
+// retryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func retryAfter(res *http.Response) (time.Duration, bool) {
+	v := res.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(v); err == nil {
+		d := time.Until(date)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
+
+// bufferBody reads the request body in memory so it can be replayed on each
+// attempt.
+func bufferBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
@@ -0,0 +1,219 @@
+package retryablehttp
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryTransport(t *testing.T) {
+	t.Run("503 then 200 yields a single successful response", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: &RetryTransport{MaxRetries: 3, MinDelay: time.Millisecond}}
+
+		res, err := client.Get(server.URL)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		body, err := ioutil.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "ok", string(body))
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("request body is replayed on each attempt", func(t *testing.T) {
+		var bodies []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: &RetryTransport{MaxRetries: 3, MinDelay: time.Millisecond}}
+
+		res, err := client.Post(server.URL, "application/json", strings.NewReader(`{"email":"marcelina@foo-corp.com"}`))
+		require.NoError(t, err)
+		res.Body.Close()
+
+		require.Equal(t, http.StatusCreated, res.StatusCode)
+		require.Equal(t, []string{
+			`{"email":"marcelina@foo-corp.com"}`,
+			`{"email":"marcelina@foo-corp.com"}`,
+			`{"email":"marcelina@foo-corp.com"}`,
+		}, bodies)
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: &RetryTransport{MaxRetries: 3, MinDelay: time.Millisecond}}
+
+		res, err := client.Get(server.URL)
+		require.NoError(t, err)
+		res.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("last response is returned once retries are exhausted", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: &RetryTransport{MaxRetries: 2, MinDelay: time.Millisecond}}
+
+		res, err := client.Get(server.URL)
+		require.NoError(t, err)
+		res.Body.Close()
+
+		require.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("context cancellation stops retries", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		client := &http.Client{Transport: &RetryTransport{MaxRetries: 3, MinDelay: time.Hour, MaxDelay: time.Hour}}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		_, err = client.Do(req.WithContext(ctx))
+		require.Error(t, err)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	res := &http.Response{Header: http.Header{}}
+	res.Header.Set("Retry-After", "2")
+
+	transport := &RetryTransport{MaxDelay: time.Minute}
+	require.Equal(t, 2*time.Second, transport.delay(0, 0, res))
+
+	transport.MaxDelay = time.Second
+	require.Equal(t, time.Second, transport.delay(0, 0, res))
+}
+
+func TestDecorrelatedJitter(t *testing.T) {
+	minDelay := 100 * time.Millisecond
+	maxDelay := 2 * time.Second
+
+	for i := 0; i < 100; i++ {
+		var prev time.Duration
+		for attempt := 0; attempt < 10; attempt++ {
+			upper := 3 * prev
+			if upper < 3*minDelay {
+				upper = 3 * minDelay
+			}
+			if upper > maxDelay {
+				upper = maxDelay
+			}
+
+			d := DecorrelatedJitter(attempt, minDelay, maxDelay, prev)
+			require.True(t, d >= minDelay, "delay %s is below %s", d, minDelay)
+			require.True(t, d <= upper, "delay %s is above %s", d, upper)
+			prev = d
+		}
+	}
+
+	require.Equal(t, minDelay, DecorrelatedJitter(0, minDelay, minDelay, 0))
+}
+
+func TestDecorrelatedJitterIgnoresGlobalSeed(t *testing.T) {
+	delays := func() []time.Duration {
+		rand.Seed(1)
+		var d []time.Duration
+		for i := 0; i < 10; i++ {
+			d = append(d, DecorrelatedJitter(0, time.Millisecond, time.Hour, time.Minute))
+		}
+		return d
+	}
+
+	require.NotEqual(t, delays(), delays())
+}
+
+func TestExponential(t *testing.T) {
+	minDelay := 100 * time.Millisecond
+	maxDelay := time.Second
+
+	var delays []time.Duration
+	for attempt := 0; attempt < 6; attempt++ {
+		delays = append(delays, Exponential(attempt, minDelay, maxDelay, 0))
+	}
+
+	require.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}, delays)
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 4 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var prevs []time.Duration
+	client := &http.Client{Transport: &RetryTransport{
+		MaxRetries: 3,
+		MinDelay:   time.Millisecond,
+		Backoff: func(attempt int, minDelay, maxDelay, prev time.Duration) time.Duration {
+			prevs = append(prevs, prev)
+			return time.Duration(attempt+1) * time.Millisecond
+		},
+	}}
+
+	res, err := client.Get(server.URL)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, []time.Duration{0, time.Millisecond, 2 * time.Millisecond}, prevs)
+}
@@ -0,0 +1,87 @@
+package sso
+
+import (
+	"sync"
+	"time"
+)
+
+// profileCache holds the responses returned by GetTokenResponse, keyed by
+// authorization code, so a callback submitted twice does not fail because its
+// code was already consumed. The cached responses do not depend on the
+// options of the call, which are applied to the returned copies.
+type profileCache struct {
+	mu      sync.Mutex
+	entries map[string]profileCacheEntry
+	calls   map[string]*profileCacheCall
+}
+
+type profileCacheEntry struct {
+	value     TokenResponse
+	expiresAt time.Time
+}
+
+// profileCacheCall is an exchange in flight, waited for by the concurrent
+// calls for the same code.
+type profileCacheCall struct {
+	wg    sync.WaitGroup
+	value TokenResponse
+	err   error
+}
+
+// do returns the cached response for the code, or calls fetch and caches its
+// successful response for ttl. Concurrent calls for a code that is not cached
+// share a single call to fetch.
+func (pc *profileCache) do(code string, ttl time.Duration, fetch func() (TokenResponse, error)) (TokenResponse, error) {
+	pc.mu.Lock()
+	if entry, ok := pc.entries[code]; ok && now().Before(entry.expiresAt) {
+		pc.mu.Unlock()
+		return entry.value, nil
+	}
+
+	if call, ok := pc.calls[code]; ok {
+		pc.mu.Unlock()
+		call.wg.Wait()
+		return call.value, call.err
+	}
+
+	call := &profileCacheCall{}
+	call.wg.Add(1)
+	if pc.calls == nil {
+		pc.calls = make(map[string]*profileCacheCall)
+	}
+	pc.calls[code] = call
+	pc.mu.Unlock()
+
+	call.value, call.err = fetch()
+
+	pc.mu.Lock()
+	delete(pc.calls, code)
+	if call.err == nil {
+		pc.set(code, call.value, ttl)
+	}
+	pc.mu.Unlock()
+	call.wg.Done()
+
+	return call.value, call.err
+}
+
+// set caches the response for the code. pc.mu must be held.
+func (pc *profileCache) set(code string, value TokenResponse, ttl time.Duration) {
+	t := now()
+	if pc.entries == nil {
+		pc.entries = make(map[string]profileCacheEntry)
+	}
+
+	// Expired entries are pruned on write so the cache does not grow
+	// unbounded.
+	for k, entry := range pc.entries {
+		if !t.Before(entry.expiresAt) {
+			delete(pc.entries, k)
+		}
+	}
+
+	pc.entries[code] = profileCacheEntry{
+		value:     value,
+		expiresAt: t.Add(ttl),
+	}
+}
@@ -0,0 +1,130 @@
+package sso
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientProfileCache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		profileAndTokenTestHandler(w, r)
+	}))
+	defer server.Close()
+
+	t.Run("the same code is fetched once within the TTL", func(t *testing.T) {
+		defer func(f func() time.Time) { now = f }(now)
+
+		fetchedAt := time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC)
+		now = func() time.Time { return fetchedAt }
+		atomic.StoreInt32(&calls, 0)
+
+		client := &Client{
+			HTTPClient:      server.Client(),
+			Endpoint:        server.URL,
+			APIKey:          "test",
+			ClientID:        "client_123",
+			ProfileCacheTTL: time.Minute,
+		}
+
+		first, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+		require.NoError(t, err)
+
+		second, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+		_, err = client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "other_code"})
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+		now = func() time.Time { return fetchedAt.Add(time.Minute) }
+		_, err = client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+		require.NoError(t, err)
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("cached results honor IncludeRaw", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+
+		client := &Client{
+			HTTPClient:      server.Client(),
+			Endpoint:        server.URL,
+			APIKey:          "test",
+			ClientID:        "client_123",
+			ProfileCacheTTL: time.Minute,
+		}
+
+		res, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "raw_code"})
+		require.NoError(t, err)
+		require.Nil(t, res.Profile.Raw)
+
+		res, err = client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "raw_code", IncludeRaw: true})
+		require.NoError(t, err)
+		require.NotEmpty(t, res.Profile.Raw)
+
+		res, err = client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "raw_code"})
+		require.NoError(t, err)
+		require.Nil(t, res.Profile.Raw)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("concurrent calls share a single exchange", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			// Hold the exchange so the other calls arrive while it is in
+			// flight.
+			time.Sleep(50 * time.Millisecond)
+			profileAndTokenTestHandler(w, r)
+		}))
+		defer server.Close()
+
+		client := &Client{
+			HTTPClient:      server.Client(),
+			Endpoint:        server.URL,
+			APIKey:          "test",
+			ClientID:        "client_123",
+			ProfileCacheTTL: time.Minute,
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				res, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "concurrent_code"})
+				require.NoError(t, err)
+				require.Equal(t, "profile_123", res.Profile.ID)
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("the cache is disabled by default", func(t *testing.T) {
+		atomic.StoreInt32(&calls, 0)
+
+		client := &Client{
+			HTTPClient: server.Client(),
+			Endpoint:   server.URL,
+			APIKey:     "test",
+			ClientID:   "client_123",
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+			require.NoError(t, err)
+		}
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
@@ -1,14 +1,19 @@
 package sso
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/go-querystring/query"
 	"github.com/workos/workos-go/v4/pkg/workos_errors"
+	"io/ioutil"
+	"log"
+	"net"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -36,6 +41,7 @@ type ConnectionType string
 const (
 	ADFSSAML              ConnectionType = "ADFSSAML"
 	AdpOidc               ConnectionType = "AdpOidc"
+	AppleOAuth            ConnectionType = "AppleOAuth"
 	Auth0SAML             ConnectionType = "Auth0SAML"
 	AzureSAML             ConnectionType = "AzureSAML"
 	CasSAML               ConnectionType = "CasSAML"
@@ -43,8 +49,10 @@ const (
 	ClassLinkSAML         ConnectionType = "ClassLinkSAML"
 	CyberArkSAML          ConnectionType = "CyberArkSAML"
 	DuoSAML               ConnectionType = "DuoSAML"
+	EntraIdOIDC           ConnectionType = "EntraIdOIDC"
 	GenericOIDC           ConnectionType = "GenericOIDC"
 	GenericSAML           ConnectionType = "GenericSAML"
+	GitHubOAuth           ConnectionType = "GitHubOAuth"
 	GoogleOAuth           ConnectionType = "GoogleOAuth"
 	GoogleSAML            ConnectionType = "GoogleSAML"
 	JumpCloudSAML         ConnectionType = "JumpCloudSAML"
@@ -55,6 +63,7 @@ const (
 	MicrosoftOAuth        ConnectionType = "MicrosoftOAuth"
 	MiniOrangeSAML        ConnectionType = "MiniOrangeSAML"
 	NetIqSAML             ConnectionType = "NetIqSAML"
+	OktaOIDC              ConnectionType = "OktaOIDC"
 	OktaSAML              ConnectionType = "OktaSAML"
 	OneLoginSAML          ConnectionType = "OneLoginSAML"
 	OracleSAML            ConnectionType = "OracleSAML"
@@ -81,6 +90,11 @@ type Client struct {
 	// REQUIRED.
 	ClientID string
 
+	// The default callback URL where your app redirects the user-agent after
+	// an authorization code is granted. It is used when
+	// GetAuthorizationURLOpts.RedirectURI is empty.
+	RedirectURI string
+
 	// The endpoint to WorkOS API.
 	//
 	// Defaults to https://api.workos.com.
@@ -91,25 +105,162 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// When true, the default http.Client is created without a timeout, so
+	// requests are only bounded by their context. It has no effect when
+	// HTTPClient is set.
+	NoDefaultTimeout bool
+
+	// The observer notified after every request sent to WorkOS. Optional.
+	Observer common.Observer
+
+	// The logger used to log every request sent to WorkOS, with its route,
+	// status and duration. Optional.
+	Logger *log.Logger
+
+	// The functions applied to every request before it is sent, e.g. to add
+	// the headers required by a proxy. An error aborts the request. Optional.
+	RequestEditors []func(*http.Request) error
+
 	// The function used to encode in JSON. Defaults to json.Marshal.
 	JSONEncode func(v interface{}) ([]byte, error)
 
-	once sync.Once
+	// The function used to decode profiles from JSON. Defaults to
+	// json.Unmarshal.
+	JSONDecode func(data []byte, v interface{}) error
+
+	// When true, the emails of the returned Profiles are lowercased.
+	NormalizeEmail bool
+
+	// The duration for which GetProfileAndToken and GetTokenResponse return
+	// the same result for an authorization code without calling WorkOS again,
+	// which guards against callbacks submitted twice. Concurrent calls for the
+	// same code share a single exchange, whose result they all receive. Zero
+	// disables the cache.
+	ProfileCacheTTL time.Duration
+
+	// The number of times the exchange of an authorization code is retried
+	// when the request could not reach WorkOS, i.e. on DNS failures and
+	// connections that could not be established. Errors occurring once the
+	// request may have been sent, such as a reset connection, and HTTP errors
+	// are never retried, since the code may have been consumed. Zero disables
+	// retries.
+	TokenExchangeRetries int
+
+	// The store used by GetProfileAndToken to reject authorization codes that
+	// were already exchanged with ErrCodeAlreadyUsed. Codes are marked before
+	// the profile cache is looked up and before being exchanged, and unmarked
+	// when the exchange fails. A callback submitted twice is thus rejected even
+	// when its result is in the profile cache. Optional.
+	UsedCodes UsedCodeStore
+
+	timeout        time.Duration
+	ownedTransport http.RoundTripper
+	profileCache   profileCache
+	once           sync.Once
 }
 
-func (c *Client) init() {
-	if c.Endpoint == "" {
-		c.Endpoint = "https://api.workos.com"
+// ClientOption configures a Client created with NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the http.Client used to send requests to WorkOS.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
 	}
-	c.Endpoint = strings.TrimSuffix(c.Endpoint, "/")
+}
+
+// WithEndpoint sets the endpoint to WorkOS API.
+func WithEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.Endpoint = endpoint
+	}
+}
+
+// WithTimeout sets the timeout of the requests sent to WorkOS. It is applied
+// after the other options, whatever their order. The http.Client is copied so
+// that a client given to WithHTTPClient is left untouched.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger used to log every request sent to WorkOS.
+func WithLogger(logger *log.Logger) ClientOption {
+	return func(c *Client) {
+		c.Logger = logger
+	}
+}
+
+// WithObserver sets the observer notified after every request sent to WorkOS.
+func WithObserver(observer common.Observer) ClientOption {
+	return func(c *Client) {
+		c.Observer = observer
+	}
+}
+
+// WithRequestEditor adds a function applied to every request before it is
+// sent to WorkOS.
+func WithRequestEditor(editor func(*http.Request) error) ClientOption {
+	return func(c *Client) {
+		c.RequestEditors = append(c.RequestEditors, editor)
+	}
+}
+
+// NewClient returns a Client configured with the given options. Setting the
+// Client fields directly remains supported.
+func NewClient(apiKey, clientID string, opts ...ClientOption) *Client {
+	c := &Client{
+		APIKey:   apiKey,
+		ClientID: clientID,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.timeout > 0 {
+		owned := c.HTTPClient == nil
+		c.HTTPClient = workos.WithTimeout(c.HTTPClient, c.timeout)
+		if owned {
+			c.ownedTransport = c.HTTPClient.Transport
+		}
+	}
+
+	return c
+}
+
+// observer returns the observer notified after every request, logging them
+// when Logger is set.
+func (c *Client) observer() common.Observer {
+	return workos.LogObserver(c.Logger, c.Observer)
+}
+
+func (c *Client) init() {
+	c.Endpoint = workos.NormalizeEndpoint(c.Endpoint, workos.DefaultEndpoint)
 
 	if c.HTTPClient == nil {
-		c.HTTPClient = &http.Client{Timeout: time.Second * 15}
+		timeout := time.Second * 15
+		if c.NoDefaultTimeout {
+			timeout = 0
+		}
+		c.HTTPClient = workos.NewHTTPClient(timeout)
+		c.ownedTransport = c.HTTPClient.Transport
 	}
 
 	if c.JSONEncode == nil {
 		c.JSONEncode = json.Marshal
 	}
+
+	if c.JSONDecode == nil {
+		c.JSONDecode = json.Unmarshal
+	}
+}
+
+// Close closes the idle connections of the transport created by the Client
+// when HTTPClient is not set. It is a no-op for a user-supplied HTTPClient.
+func (c *Client) Close() {
+	workos.CloseIdleConnections(c.HTTPClient, c.ownedTransport)
 }
 
 // GetLoginHandler returns an http.Handler that redirects client to the appropriate
@@ -142,8 +293,9 @@ type GetAuthorizationURLOpts struct {
 	// OPTIONAL.
 	LoginHint string
 
-	// Authentication service provider descriptor.
-	// Provider is currently only used when the connection type is GoogleOAuth.
+	// Authentication service provider descriptor. Only the OAuth connection
+	// types, AppleOAuth, GitHubOAuth, GoogleOAuth and MicrosoftOAuth, are
+	// valid providers.
 	Provider ConnectionType
 
 	// The unique identifier for a WorkOS Connection.
@@ -154,8 +306,9 @@ type GetAuthorizationURLOpts struct {
 
 	// The callback URL where your app redirects the user-agent after an
 	// authorization code is granted (eg. https://foo.com/callback).
+	// Overrides the Client RedirectURI when set.
 	//
-	// REQUIRED.
+	// REQUIRED when the Client has no RedirectURI.
 	RedirectURI string
 
 	// A unique identifier used to manage state across authorization
@@ -163,14 +316,40 @@ type GetAuthorizationURLOpts struct {
 	//
 	// OPTIONAL.
 	State string
+
+	// Additional OAuth scopes requested from the provider, such as Google's
+	// profile or email scopes. They are sent space separated.
+	//
+	// OPTIONAL.
+	Scopes []string
 }
 
 // GetAuthorizationURL returns an authorization url generated with the given
 // options.
 func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
+	return c.GetAuthorizationURLContext(context.Background(), opts)
+}
+
+// GetAuthorizationURLContext is like GetAuthorizationURL but takes a context.
+// No request is sent to WorkOS; an error is returned when the context is
+// already done.
+func (c *Client) GetAuthorizationURLContext(ctx context.Context, opts GetAuthorizationURLOpts) (*url.URL, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	c.once.Do(c.init)
 
 	redirectURI := opts.RedirectURI
+	if redirectURI == "" {
+		redirectURI = c.RedirectURI
+	}
+	if redirectURI == "" {
+		return nil, errors.New("incomplete arguments: missing RedirectURI")
+	}
+	if u, err := url.Parse(redirectURI); err != nil || !u.IsAbs() || u.Host == "" {
+		return nil, fmt.Errorf("invalid RedirectURI %q: must be an absolute URL", redirectURI)
+	}
 
 	query := make(url.Values, 5)
 	query.Set("client_id", c.ClientID)
@@ -181,6 +360,9 @@ func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, er
 		return nil, errors.New("incomplete arguments: missing connection, organization, domain, or provider")
 	}
 	if opts.Provider != "" {
+		if !isProvider(opts.Provider) {
+			return nil, fmt.Errorf("invalid Provider %q: must be one of %s", opts.Provider, providerNames())
+		}
 		query.Set("provider", string(opts.Provider))
 	}
 	if opts.Domain != "" {
@@ -205,6 +387,10 @@ func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, er
 		query.Set("state", opts.State)
 	}
 
+	if len(opts.Scopes) != 0 {
+		query.Set("scope", strings.Join(opts.Scopes, " "))
+	}
+
 	u, err := url.ParseRequestURI(c.Endpoint + "/sso/authorize")
 	if err != nil {
 		return nil, err
@@ -214,11 +400,70 @@ func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, er
 	return u, nil
 }
 
+// GetAuthorizationURLs returns the authorization urls generated with each of
+// the given options, in order. It stops at the first invalid options and
+// reports their index.
+func (c *Client) GetAuthorizationURLs(opts []GetAuthorizationURLOpts) ([]*url.URL, error) {
+	urls := make([]*url.URL, 0, len(opts))
+	for i, o := range opts {
+		u, err := c.GetAuthorizationURL(o)
+		if err != nil {
+			return nil, fmt.Errorf("authorization url %d: %w", i, err)
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+// providers lists the OAuth connection types that can be used as the
+// Provider of an authorization URL.
+var providers = []ConnectionType{
+	AppleOAuth,
+	GitHubOAuth,
+	GoogleOAuth,
+	MicrosoftOAuth,
+}
+
+// isProvider reports whether the connection type can be used as the Provider
+// of an authorization URL.
+func isProvider(t ConnectionType) bool {
+	for _, p := range providers {
+		if t == p {
+			return true
+		}
+	}
+	return false
+}
+
+// providerNames returns the comma-separated names of the providers.
+func providerNames() string {
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		names = append(names, string(p))
+	}
+	return strings.Join(names, ", ")
+}
+
 // GetProfileAndTokenOpts contains the options to pass in order to get a user profile and access token.
 type GetProfileAndTokenOpts struct {
 	// An opaque string provided by the authorization server. It will be
 	// exchanged for an Access Token when the user’s profile is sent.
 	Code string
+
+	// The maximum duration of the call, layered over the context. Zero means
+	// the call is only bounded by the context and the HTTP client.
+	Timeout time.Duration
+
+	// When true, the Profile JSON returned by WorkOS is kept in Profile.Raw,
+	// e.g. to debug identity provider issues. It may contain sensitive data.
+	IncludeRaw bool
+
+	// The ID of the Organization the Profile must belong to. When set, a
+	// Profile of another Organization is rejected with an error wrapping
+	// ErrOrganizationMismatch.
+	//
+	// OPTIONAL.
+	ExpectedOrganizationID string
 }
 
 // Profile contains information about an authenticated user.
@@ -252,6 +497,22 @@ type Profile struct {
 
 	// The raw response of Profile attributes from the identity provider
 	RawAttributes map[string]interface{} `json:"raw_attributes"`
+
+	// The Profile JSON returned by WorkOS. It is only set when IncludeRaw is
+	// requested.
+	Raw json.RawMessage `json:"-"`
+}
+
+// FullName returns the first and last names of the Profile, falling back to
+// the local part of its email when both are empty.
+func (p Profile) FullName() string {
+	return workos.FullName(p.FirstName, p.LastName, p.Email)
+}
+
+// EmailDomain returns the lowercased domain of the email of the Profile, or an
+// empty string when the email is malformed.
+func (p Profile) EmailDomain() string {
+	return workos.EmailDomain(p.Email)
 }
 
 type ProfileAndToken struct {
@@ -262,45 +523,220 @@ type ProfileAndToken struct {
 	Profile Profile `json:"profile"`
 }
 
+// TokenResponse is the full response of the WorkOS SSO token endpoint.
+type TokenResponse struct {
+	// An access token corresponding to the Profile.
+	AccessToken string `json:"access_token"`
+
+	// The number of seconds the access token is valid for, when returned by
+	// WorkOS.
+	ExpiresIn int `json:"expires_in,omitempty"`
+
+	// The user Profile.
+	Profile Profile `json:"profile"`
+
+	// The JSON returned by WorkOS, including the fields not decoded in the
+	// TokenResponse.
+	RawResponse json.RawMessage `json:"-"`
+}
+
 // GetProfileAndToken returns a profile describing the user that authenticated with
 // WorkOS SSO.
 func (c *Client) GetProfileAndToken(ctx context.Context, opts GetProfileAndTokenOpts) (ProfileAndToken, error) {
+	body, err := c.GetTokenResponse(ctx, opts)
+	if err != nil {
+		return ProfileAndToken{}, err
+	}
+
+	return ProfileAndToken{
+		AccessToken: body.AccessToken,
+		Profile:     body.Profile,
+	}, nil
+}
+
+// GetTokenResponse exchanges an authorization code like GetProfileAndToken
+// and returns the full response of WorkOS, so fields added to it are not lost.
+func (c *Client) GetTokenResponse(ctx context.Context, opts GetProfileAndTokenOpts) (TokenResponse, error) {
+	body, err := c.getTokenResponse(ctx, opts)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	if err = checkOrganization(opts.ExpectedOrganizationID, body.Profile); err != nil {
+		return TokenResponse{}, err
+	}
+	return body, nil
+}
+
+func (c *Client) getTokenResponse(ctx context.Context, opts GetProfileAndTokenOpts) (TokenResponse, error) {
 	c.once.Do(c.init)
 
+	if c.APIKey == "" {
+		return TokenResponse{}, workos_errors.ErrMissingAPIKey
+	}
+
+	ctx, cancel := withTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	if err := c.markCodeUsed(ctx, opts.Code); err != nil {
+		return TokenResponse{}, err
+	}
+
+	exchange := func() (TokenResponse, error) {
+		return c.exchangeCode(ctx, opts.Code)
+	}
+
+	var body TokenResponse
+	var err error
+	if c.ProfileCacheTTL > 0 {
+		body, err = c.profileCache.do(opts.Code, c.ProfileCacheTTL, exchange)
+	} else {
+		body, err = exchange()
+	}
+	if err != nil {
+		c.unmarkCodeUsed(opts.Code)
+		return TokenResponse{}, err
+	}
+
+	if !opts.IncludeRaw {
+		body.Profile.Raw = nil
+	}
+	return body, nil
+}
+
+// exchangeCode exchanges the authorization code for a token and a Profile.
+// The Profile JSON is always kept in Profile.Raw so that the result does not
+// depend on the options of the call and can be cached.
+func (c *Client) exchangeCode(ctx context.Context, code string) (TokenResponse, error) {
 	form := make(url.Values, 5)
 	form.Set("client_id", c.ClientID)
 	form.Set("client_secret", c.APIKey)
 	form.Set("grant_type", "authorization_code")
-	form.Set("code", opts.Code)
+	form.Set("code", code)
+
+	var res *http.Response
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(
+			http.MethodPost,
+			c.Endpoint+"/sso/token",
+			strings.NewReader(form.Encode()),
+		)
+		if err != nil {
+			return TokenResponse{}, err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("User-Agent", common.UserAgent())
+		workos.SetCorrelationID(req)
 
-	req, err := http.NewRequest(
-		http.MethodPost,
-		c.Endpoint+"/sso/token",
-		strings.NewReader(form.Encode()),
-	)
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+		res, err = workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
+		if err == nil {
+			break
+		}
+		if attempt >= c.TokenExchangeRetries || !isDialError(ctx, err) {
+			return TokenResponse{}, err
+		}
+	}
+	defer res.Body.Close()
+
+	if err := tryGetAuthorizationError(res); err != nil {
+		return TokenResponse{}, err
+	}
+
+	if err := workos_errors.TryGetHTTPError(res); err != nil {
+		return TokenResponse{}, err
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
 	if err != nil {
-		return ProfileAndToken{}, err
+		return TokenResponse{}, err
 	}
-	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
 
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	var body TokenResponse
+	if err = c.JSONDecode(data, &body); err != nil {
+		return TokenResponse{}, err
+	}
+	body.RawResponse = data
+
+	var raw struct {
+		Profile json.RawMessage `json:"profile"`
+	}
+	if err = json.Unmarshal(data, &raw); err != nil {
+		return TokenResponse{}, err
+	}
+	body.Profile.Raw = raw.Profile
+
+	c.normalizeProfile(&body.Profile)
+
+	return body, nil
+}
+
+// isDialError reports whether err means that the request could not reach
+// WorkOS, because of a DNS failure or a connection that could not be
+// established, while the context is still valid. Errors occurring once the
+// request may have been sent, such as a reset connection, are excluded since
+// WorkOS may have consumed the code.
+func isDialError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
 
-	res, err := c.HTTPClient.Do(req)
+// normalizeProfile lowercases the email of the Profile when NormalizeEmail is
+// set.
+func (c *Client) normalizeProfile(p *Profile) {
+	if c.NormalizeEmail {
+		p.Email = strings.ToLower(p.Email)
+	}
+}
+
+// AuthorizationError is returned when an authorization code can't be exchanged
+// because it is invalid, expired or has already been used.
+type AuthorizationError struct {
+	// The OAuth error code (eg. invalid_grant).
+	Code string `json:"error"`
+
+	// The human readable description of the error.
+	Description string `json:"error_description"`
+}
+
+func (e AuthorizationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Description)
+}
+
+// tryGetAuthorizationError returns an AuthorizationError when the response
+// rejects the authorization code. Otherwise the response body is left
+// untouched.
+func tryGetAuthorizationError(r *http.Response) error {
+	if r.StatusCode < 400 || r.StatusCode >= 500 {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		return ProfileAndToken{}, err
+		return err
 	}
-	defer res.Body.Close()
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
 
-	if err = workos_errors.TryGetHTTPError(res); err != nil {
-		return ProfileAndToken{}, err
+	var authErr AuthorizationError
+	if err := json.Unmarshal(body, &authErr); err != nil {
+		return nil
 	}
 
-	var body ProfileAndToken
-	dec := json.NewDecoder(res.Body)
-	err = dec.Decode(&body)
+	if authErr.Code != "invalid_grant" {
+		return nil
+	}
 
-	return body, err
+	return authErr
 }
 
 // GetProfile contains the options to pass in order to get a user profile.
@@ -308,6 +744,21 @@ type GetProfileOpts struct {
 	// An opaque string provided by the authorization server. It will be
 	// exchanged for an Access Token when the user’s profile is sent.
 	AccessToken string
+
+	// The maximum duration of the call, layered over the context. Zero means
+	// the call is only bounded by the context and the HTTP client.
+	Timeout time.Duration
+
+	// When true, the Profile JSON returned by WorkOS is kept in Profile.Raw,
+	// e.g. to debug identity provider issues. It may contain sensitive data.
+	IncludeRaw bool
+
+	// The ID of the Organization the Profile must belong to. When set, a
+	// Profile of another Organization is rejected with an error wrapping
+	// ErrOrganizationMismatch.
+	//
+	// OPTIONAL.
+	ExpectedOrganizationID string
 }
 
 // GetProfile returns a profile describing the user that authenticated with
@@ -315,6 +766,9 @@ type GetProfileOpts struct {
 func (c *Client) GetProfile(ctx context.Context, opts GetProfileOpts) (Profile, error) {
 	c.once.Do(c.init)
 
+	ctx, cancel := withTimeout(ctx, opts.Timeout)
+	defer cancel()
+
 	req, err := http.NewRequest(
 		http.MethodGet,
 		c.Endpoint+"/sso/profile",
@@ -325,9 +779,10 @@ func (c *Client) GetProfile(ctx context.Context, opts GetProfileOpts) (Profile,
 	}
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+opts.AccessToken)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return Profile{}, err
 	}
@@ -337,11 +792,26 @@ func (c *Client) GetProfile(ctx context.Context, opts GetProfileOpts) (Profile,
 		return Profile{}, err
 	}
 
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return Profile{}, err
+	}
+
 	var body Profile
-	dec := json.NewDecoder(res.Body)
-	err = dec.Decode(&body)
+	if err = c.JSONDecode(data, &body); err != nil {
+		return body, err
+	}
 
-	return body, err
+	if opts.IncludeRaw {
+		body.Raw = json.RawMessage(data)
+	}
+
+	c.normalizeProfile(&body)
+
+	if err = checkOrganization(opts.ExpectedOrganizationID, body); err != nil {
+		return Profile{}, err
+	}
+	return body, nil
 }
 
 // ConnectionDomain represents the domain records associated with a Connection.
@@ -399,10 +869,29 @@ type Connection struct {
 	Domains []ConnectionDomain `json:"domains"`
 
 	// The timestamp of when the Connection was created.
-	CreatedAt string `json:"created_at"`
+	CreatedAt time.Time `json:"created_at"`
 
 	// The timestamp of when the Connection was updated.
-	UpdatedAt string `json:"updated_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UnmarshalJSON decodes the Connection, treating null and empty timestamps as the
+// zero time.
+func (c *Connection) UnmarshalJSON(data []byte) error {
+	type connection Connection
+	var v struct {
+		connection
+		CreatedAt workos.NullableTime `json:"created_at"`
+		UpdatedAt workos.NullableTime `json:"updated_at"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*c = Connection(v.connection)
+	c.CreatedAt = v.CreatedAt.Time
+	c.UpdatedAt = v.UpdatedAt.Time
+	return nil
 }
 
 // GetConnectionOpts contains the options to request details for a Connection.
@@ -435,9 +924,10 @@ func (c *Client) GetConnection(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return Connection{}, err
 	}
@@ -464,6 +954,10 @@ type ListConnectionsOpts struct {
 	// Domain of a Connection. Can be empty.
 	Domain string `url:"domain,omitempty"`
 
+	// State of the Connection(s), such as Inactive or Validating. Can be
+	// empty.
+	State ConnectionState `url:"state,omitempty"`
+
 	// Maximum number of records to return.
 	Limit int `url:"limit"`
 
@@ -477,6 +971,23 @@ type ListConnectionsOpts struct {
 	After string `url:"after,omitempty"`
 }
 
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListConnectionsOpts) NextPage(m common.ListMetadata) ListConnectionsOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListConnectionsOpts) PrevPage(m common.ListMetadata) ListConnectionsOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
 // ListConnectionsResponse describes the response structure when requesting
 // existing Connections.
 type ListConnectionsResponse struct {
@@ -507,10 +1018,9 @@ func (c *Client) ListConnections(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	if opts.Order == "" {
 		opts.Order = Desc
@@ -522,7 +1032,7 @@ func (c *Client) ListConnections(
 	}
 
 	req.URL.RawQuery = v.Encode()
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return ListConnectionsResponse{}, err
 	}
@@ -538,6 +1048,394 @@ func (c *Client) ListConnections(
 	return body, err
 }
 
+// GetConnectionByOrganizationOpts contains the options to get the Connection
+// of an Organization.
+type GetConnectionByOrganizationOpts struct {
+	// Organization unique identifier.
+	//
+	// REQUIRED.
+	OrganizationID string
+}
+
+// GetConnectionByOrganization returns the Connection of the given
+// Organization. Active Connections are preferred when the Organization has
+// several. It returns false with a nil error when the Organization has no
+// Connection.
+func (c *Client) GetConnectionByOrganization(
+	ctx context.Context,
+	opts GetConnectionByOrganizationOpts,
+) (Connection, bool, error) {
+	if opts.OrganizationID == "" {
+		return Connection{}, false, errors.New("incomplete arguments: missing OrganizationID")
+	}
+
+	res, err := c.ListConnections(ctx, ListConnectionsOpts{
+		OrganizationID: opts.OrganizationID,
+	})
+	if err != nil {
+		return Connection{}, false, err
+	}
+
+	if len(res.Data) == 0 {
+		return Connection{}, false, nil
+	}
+
+	for _, connection := range res.Data {
+		if connection.State == Active {
+			return connection, true, nil
+		}
+	}
+
+	return res.Data[0], true, nil
+}
+
+// UpdateConnectionOpts contains the options to update a Connection.
+type UpdateConnectionOpts struct {
+	// Connection unique identifier.
+	//
+	// REQUIRED.
+	Connection string
+
+	// Name of the Connection.
+	Name string
+
+	// Domains of the Connection.
+	Domains []string
+}
+
+// UpdateConnection updates the mutable attributes of a Connection.
+func (c *Client) UpdateConnection(
+	ctx context.Context,
+	opts UpdateConnectionOpts,
+) (Connection, error) {
+	if opts.Connection == "" {
+		return Connection{}, errors.New("incomplete arguments: missing Connection")
+	}
+
+	// updateConnectionChangeOpts contains the options to update a Connection minus the connection ID
+	type updateConnectionChangeOpts struct {
+		Name    string   `json:"name,omitempty"`
+		Domains []string `json:"domains,omitempty"`
+	}
+
+	return c.updateConnection(ctx, opts.Connection, updateConnectionChangeOpts{opts.Name, opts.Domains})
+}
+
+// ConnectionDomainOpts contains the options to add or remove a domain of a
+// Connection.
+type ConnectionDomainOpts struct {
+	// Connection unique identifier.
+	//
+	// REQUIRED.
+	Connection string
+
+	// The domain to add or remove (eg. foo-corp.com).
+	//
+	// REQUIRED.
+	Domain string
+}
+
+// AddConnectionDomain adds a domain to a Connection and returns the updated
+// Connection. Adding a domain that the Connection already has is a no-op.
+func (c *Client) AddConnectionDomain(
+	ctx context.Context,
+	opts ConnectionDomainOpts,
+) (Connection, error) {
+	return c.changeConnectionDomains(ctx, opts, func(domains []string) []string {
+		for _, d := range domains {
+			if strings.EqualFold(d, opts.Domain) {
+				return domains
+			}
+		}
+		return append(domains, opts.Domain)
+	})
+}
+
+// RemoveConnectionDomain removes a domain from a Connection and returns the
+// updated Connection.
+func (c *Client) RemoveConnectionDomain(
+	ctx context.Context,
+	opts ConnectionDomainOpts,
+) (Connection, error) {
+	return c.changeConnectionDomains(ctx, opts, func(domains []string) []string {
+		kept := []string{}
+		for _, d := range domains {
+			if !strings.EqualFold(d, opts.Domain) {
+				kept = append(kept, d)
+			}
+		}
+		return kept
+	})
+}
+
+func (c *Client) changeConnectionDomains(
+	ctx context.Context,
+	opts ConnectionDomainOpts,
+	change func(domains []string) []string,
+) (Connection, error) {
+	if opts.Connection == "" {
+		return Connection{}, errors.New("incomplete arguments: missing Connection")
+	}
+	if !isDomain(opts.Domain) {
+		return Connection{}, fmt.Errorf("invalid Domain %q", opts.Domain)
+	}
+
+	connection, err := c.GetConnection(ctx, GetConnectionOpts{Connection: opts.Connection})
+	if err != nil {
+		return Connection{}, err
+	}
+
+	domains := make([]string, 0, len(connection.Domains))
+	for _, d := range connection.Domains {
+		domains = append(domains, d.Domain)
+	}
+
+	// The domains are always sent so that the last domain can be removed.
+	type updateConnectionDomainsOpts struct {
+		Domains []string `json:"domains"`
+	}
+
+	return c.updateConnection(ctx, opts.Connection, updateConnectionDomainsOpts{change(domains)})
+}
+
+func (c *Client) updateConnection(
+	ctx context.Context,
+	connection string,
+	changes interface{},
+) (Connection, error) {
+	c.once.Do(c.init)
+
+	data, err := c.JSONEncode(changes)
+	if err != nil {
+		return Connection{}, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/connections/%s",
+		c.Endpoint,
+		connection,
+	)
+	req, err := http.NewRequest(
+		http.MethodPut,
+		endpoint,
+		bytes.NewBuffer(data),
+	)
+	if err != nil {
+		return Connection{}, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
+	if err != nil {
+		return Connection{}, err
+	}
+	defer res.Body.Close()
+
+	if err = workos_errors.TryGetHTTPError(res); err != nil {
+		return Connection{}, err
+	}
+
+	var body Connection
+	dec := json.NewDecoder(res.Body)
+	err = dec.Decode(&body)
+	return body, err
+}
+
+var domainRegexp = regexp.MustCompile(`^(?i)([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// isDomain reports whether s is a valid domain name.
+func isDomain(s string) bool {
+	return len(s) <= 253 && domainRegexp.MatchString(s)
+}
+
+// CreateConnectionOpts contains the options to create a Connection.
+type CreateConnectionOpts struct {
+	// Name of the Connection.
+	Name string `json:"name,omitempty"`
+
+	// Connection provider type.
+	//
+	// REQUIRED.
+	ConnectionType ConnectionType `json:"connection_type"`
+
+	// Organization ID of the Connection.
+	//
+	// REQUIRED.
+	OrganizationID string `json:"organization_id"`
+
+	// Domains of the Connection.
+	Domains []string `json:"domains,omitempty"`
+
+	// URL of the IdP SAML metadata. SAML Connections require either
+	// SAMLMetadataURL or SAMLMetadataXML.
+	SAMLMetadataURL string `json:"saml_idp_metadata_url,omitempty"`
+
+	// Raw XML of the IdP SAML metadata.
+	SAMLMetadataXML string `json:"saml_idp_metadata_xml,omitempty"`
+}
+
+// CreateConnection creates a Connection.
+func (c *Client) CreateConnection(
+	ctx context.Context,
+	opts CreateConnectionOpts,
+) (Connection, error) {
+	c.once.Do(c.init)
+
+	if opts.ConnectionType == "" {
+		return Connection{}, errors.New("incomplete arguments: missing ConnectionType")
+	}
+	if opts.OrganizationID == "" {
+		return Connection{}, errors.New("incomplete arguments: missing OrganizationID")
+	}
+	if isSAML(opts.ConnectionType) && opts.SAMLMetadataURL == "" && opts.SAMLMetadataXML == "" {
+		return Connection{}, errors.New("incomplete arguments: missing SAMLMetadataURL or SAMLMetadataXML")
+	}
+
+	data, err := c.JSONEncode(opts)
+	if err != nil {
+		return Connection{}, err
+	}
+
+	endpoint := fmt.Sprintf("%s/connections", c.Endpoint)
+	req, err := http.NewRequest(
+		http.MethodPost,
+		endpoint,
+		bytes.NewBuffer(data),
+	)
+	if err != nil {
+		return Connection{}, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
+	if err != nil {
+		return Connection{}, err
+	}
+	defer res.Body.Close()
+
+	if err = workos_errors.TryGetHTTPError(res); err != nil {
+		return Connection{}, err
+	}
+
+	var body Connection
+	dec := json.NewDecoder(res.Body)
+	err = dec.Decode(&body)
+	return body, err
+}
+
+// AuthorizeLogoutOpts contains the options to authorize the logout of a user
+// signed in with SSO.
+type AuthorizeLogoutOpts struct {
+	// The ID of the Profile of the user to log out.
+	//
+	// REQUIRED.
+	ProfileID string `json:"profile_id"`
+}
+
+// LogoutAuthorization contains the token used to log out a user signed in
+// with SSO.
+type LogoutAuthorization struct {
+	// The URL to redirect the user to in order to log out.
+	LogoutURL string `json:"logout_url"`
+
+	// The token to pass to GetLogoutURL.
+	LogoutToken string `json:"logout_token"`
+}
+
+// AuthorizeLogout authorizes the logout of a user signed in with SSO. The user
+// must then be redirected to the returned LogoutURL, which ends the session
+// with the identity provider when it supports single logout.
+func (c *Client) AuthorizeLogout(
+	ctx context.Context,
+	opts AuthorizeLogoutOpts,
+) (LogoutAuthorization, error) {
+	c.once.Do(c.init)
+
+	if opts.ProfileID == "" {
+		return LogoutAuthorization{}, errors.New("incomplete arguments: missing ProfileID")
+	}
+
+	data, err := c.JSONEncode(opts)
+	if err != nil {
+		return LogoutAuthorization{}, err
+	}
+
+	endpoint := fmt.Sprintf("%s/sso/logout/authorize", c.Endpoint)
+	req, err := http.NewRequest(
+		http.MethodPost,
+		endpoint,
+		bytes.NewBuffer(data),
+	)
+	if err != nil {
+		return LogoutAuthorization{}, err
+	}
+
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
+	if err != nil {
+		return LogoutAuthorization{}, err
+	}
+	defer res.Body.Close()
+
+	if err = workos_errors.TryGetHTTPError(res); err != nil {
+		return LogoutAuthorization{}, err
+	}
+
+	var body LogoutAuthorization
+	dec := json.NewDecoder(res.Body)
+	err = dec.Decode(&body)
+	return body, err
+}
+
+// GetLogoutURLOpts contains the options to generate the logout URL of a user
+// signed in with SSO.
+type GetLogoutURLOpts struct {
+	// The token returned by AuthorizeLogout.
+	//
+	// REQUIRED.
+	Token string
+}
+
+// GetLogoutURL generates the URL to redirect the user to in order to log out.
+func (c *Client) GetLogoutURL(opts GetLogoutURLOpts) (*url.URL, error) {
+	c.once.Do(c.init)
+
+	if opts.Token == "" {
+		return nil, errors.New("incomplete arguments: missing Token")
+	}
+
+	u, err := url.ParseRequestURI(c.Endpoint + "/sso/logout")
+	if err != nil {
+		return nil, err
+	}
+
+	query := make(url.Values, 1)
+	query.Set("token", opts.Token)
+	u.RawQuery = query.Encode()
+
+	return u, nil
+}
+
+// isSAML reports whether the connection type is a SAML one.
+func isSAML(t ConnectionType) bool {
+	return strings.HasSuffix(string(t), "SAML")
+}
+
 // DeleteConnectionOpts contains the options to delete a Connection.
 type DeleteConnectionOpts struct {
 	// Connection unique identifier.
@@ -568,9 +1466,10 @@ func (c *Client) DeleteConnection(
 	req = req.WithContext(ctx)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
@@ -578,3 +1477,12 @@ func (c *Client) DeleteConnection(
 
 	return workos_errors.TryGetHTTPError(res)
 }
+
+// withTimeout returns a copy of ctx bounded by the given timeout, or ctx
+// itself when the timeout is not positive.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -1,16 +1,25 @@
 package sso
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/workos/workos-go/v4/pkg/common"
+	"github.com/workos/workos-go/v4/pkg/workos_errors"
 )
 
 func TestClientAuthorizeURL(t *testing.T) {
@@ -124,6 +133,190 @@ func TestClientAuthorizeURLWithNoConnectionDomainAndProvider(t *testing.T) {
 	require.Nil(t, u)
 }
 
+func TestClientAuthorizeURLWithOAuthProviders(t *testing.T) {
+	client := Client{
+		APIKey:   "test",
+		ClientID: "client_123",
+	}
+
+	for _, provider := range []ConnectionType{AppleOAuth, GitHubOAuth, GoogleOAuth, MicrosoftOAuth} {
+		t.Run(string(provider), func(t *testing.T) {
+			u, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+				Provider:    provider,
+				RedirectURI: "https://example.com/sso/workos/callback",
+			})
+
+			require.NoError(t, err)
+			require.Equal(t, string(provider), u.Query().Get("provider"))
+		})
+	}
+}
+
+func TestClientAuthorizeURLWithInvalidProvider(t *testing.T) {
+	client := Client{
+		APIKey:   "test",
+		ClientID: "client_123",
+	}
+
+	u, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+		Provider:    OktaSAML,
+		RedirectURI: "https://example.com/sso/workos/callback",
+		State:       "state",
+	})
+
+	require.EqualError(t, err, `invalid Provider "OktaSAML": must be one of AppleOAuth, GitHubOAuth, GoogleOAuth, MicrosoftOAuth`)
+	require.Nil(t, u)
+
+	u, err = client.GetAuthorizationURL(GetAuthorizationURLOpts{
+		Provider:    MicrosoftOAuth,
+		RedirectURI: "https://example.com/sso/workos/callback",
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, "MicrosoftOAuth", u.Query().Get("provider"))
+}
+
+func TestClientAuthorizeURLScopes(t *testing.T) {
+	client := Client{
+		APIKey:   "test",
+		ClientID: "client_123",
+	}
+
+	u, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+		Provider:    GoogleOAuth,
+		RedirectURI: "https://example.com/sso/workos/callback",
+		Scopes:      []string{"profile", "email"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "profile email", u.Query().Get("scope"))
+	require.Contains(t, u.RawQuery, "scope=profile+email")
+
+	u, err = client.GetAuthorizationURL(GetAuthorizationURLOpts{
+		Provider:    GoogleOAuth,
+		RedirectURI: "https://example.com/sso/workos/callback",
+	})
+	require.NoError(t, err)
+	require.NotContains(t, u.Query(), "scope")
+}
+
+func TestClientAuthorizeURLContext(t *testing.T) {
+	client := Client{
+		APIKey:   "test",
+		ClientID: "client_123",
+	}
+	opts := GetAuthorizationURLOpts{
+		Organization: "organization_123",
+		RedirectURI:  "https://example.com/sso/workos/callback",
+		State:        "custom state",
+	}
+
+	expected, err := client.GetAuthorizationURL(opts)
+	require.NoError(t, err)
+
+	u, err := client.GetAuthorizationURLContext(context.Background(), opts)
+	require.NoError(t, err)
+	require.Equal(t, expected.String(), u.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	u, err = client.GetAuthorizationURLContext(ctx, opts)
+	require.Equal(t, context.Canceled, err)
+	require.Nil(t, u)
+}
+
+func TestClientAuthorizeURLs(t *testing.T) {
+	client := Client{
+		APIKey:      "test",
+		ClientID:    "client_123",
+		RedirectURI: "https://example.com/sso/workos/callback",
+	}
+
+	t.Run("urls are built in order", func(t *testing.T) {
+		urls, err := client.GetAuthorizationURLs([]GetAuthorizationURLOpts{
+			{Provider: GoogleOAuth},
+			{Provider: MicrosoftOAuth},
+			{Connection: "connection_123", State: "state"},
+		})
+		require.NoError(t, err)
+		require.Len(t, urls, 3)
+		require.Equal(t, "GoogleOAuth", urls[0].Query().Get("provider"))
+		require.Equal(t, "MicrosoftOAuth", urls[1].Query().Get("provider"))
+		require.Equal(t, "connection_123", urls[2].Query().Get("connection"))
+		require.Equal(t, "state", urls[2].Query().Get("state"))
+	})
+
+	t.Run("the index of invalid options is reported", func(t *testing.T) {
+		urls, err := client.GetAuthorizationURLs([]GetAuthorizationURLOpts{
+			{Provider: GoogleOAuth},
+			{Provider: OktaSAML},
+			{},
+		})
+		require.EqualError(t, err, `authorization url 1: invalid Provider "OktaSAML": must be one of AppleOAuth, GitHubOAuth, GoogleOAuth, MicrosoftOAuth`)
+		require.Nil(t, urls)
+	})
+}
+
+func TestClientAuthorizeURLRedirectURI(t *testing.T) {
+	t.Run("client RedirectURI is used by default", func(t *testing.T) {
+		client := Client{
+			APIKey:      "test",
+			ClientID:    "client_123",
+			RedirectURI: "https://example.com/sso/workos/callback",
+		}
+
+		u, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+			Connection: "connection_123",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://example.com/sso/workos/callback", u.Query().Get("redirect_uri"))
+	})
+
+	t.Run("options RedirectURI takes precedence", func(t *testing.T) {
+		client := Client{
+			APIKey:      "test",
+			ClientID:    "client_123",
+			RedirectURI: "https://example.com/sso/workos/callback",
+		}
+
+		u, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+			Connection:  "connection_123",
+			RedirectURI: "https://staging.example.com/sso/workos/callback",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://staging.example.com/sso/workos/callback", u.Query().Get("redirect_uri"))
+	})
+
+	t.Run("missing RedirectURI returns an error", func(t *testing.T) {
+		client := Client{
+			APIKey:   "test",
+			ClientID: "client_123",
+		}
+
+		u, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+			Connection: "connection_123",
+		})
+		require.EqualError(t, err, "incomplete arguments: missing RedirectURI")
+		require.Nil(t, u)
+	})
+
+	t.Run("invalid RedirectURI returns an error", func(t *testing.T) {
+		client := Client{
+			APIKey:   "test",
+			ClientID: "client_123",
+		}
+
+		for _, redirectURI := range []string{"/sso/workos/callback", "example.com/callback", "https://"} {
+			u, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+				Connection:  "connection_123",
+				RedirectURI: redirectURI,
+			})
+			require.Error(t, err, redirectURI)
+			require.Nil(t, u)
+		}
+	})
+}
+
 func TestClientGetProfileAndToken(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -235,6 +428,132 @@ func profileAndTokenTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(b)
 }
 
+func TestClientGetProfileAndTokenAuthorizationError(t *testing.T) {
+	t.Run("invalid_grant is returned as an AuthorizationError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant","error_description":"The code 'authorization_code' has expired or is invalid."}`))
+		}))
+		defer server.Close()
+
+		client := &Client{
+			APIKey:     "test",
+			ClientID:   "client_123",
+			Endpoint:   server.URL,
+			HTTPClient: server.Client(),
+		}
+
+		_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{
+			Code: "authorization_code",
+		})
+
+		var authErr AuthorizationError
+		require.True(t, errors.As(err, &authErr))
+		require.Equal(t, "invalid_grant", authErr.Code)
+		require.Equal(t, "The code 'authorization_code' has expired or is invalid.", authErr.Description)
+	})
+
+	t.Run("other errors are returned as an HTTPError", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"invalid_client","error_description":"Invalid client secret."}`))
+		}))
+		defer server.Close()
+
+		client := &Client{
+			APIKey:     "test",
+			ClientID:   "client_123",
+			Endpoint:   server.URL,
+			HTTPClient: server.Client(),
+		}
+
+		_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{
+			Code: "authorization_code",
+		})
+
+		var httpErr workos_errors.HTTPError
+		require.True(t, errors.As(err, &httpErr))
+		require.Equal(t, "invalid_client Invalid client secret.", httpErr.Message)
+	})
+}
+
+func TestClientGetProfileAndTokenJSONDecode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token":"01DMEK0J53CVMC32CK5SE0KZ8Q","profile":{"id":"profile_123","raw_attributes":{"employee_number":9007199254740993}}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		ClientID:   "client_123",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+		JSONDecode: func(data []byte, v interface{}) error {
+			dec := json.NewDecoder(bytes.NewReader(data))
+			dec.UseNumber()
+			return dec.Decode(v)
+		},
+	}
+
+	profileAndToken, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{
+		Code: "authorization_code",
+	})
+	require.NoError(t, err)
+
+	employeeNumber, ok := profileAndToken.Profile.RawAttributes["employee_number"].(json.Number)
+	require.True(t, ok)
+
+	n, err := employeeNumber.Int64()
+	require.NoError(t, err)
+	require.Equal(t, int64(9007199254740993), n)
+}
+
+func TestClientNormalizeEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sso/profile" {
+			w.Write([]byte(`{"id":"profile_123","email":"Foo@Example.com"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"01DMEK0J53CVMC32CK5SE0KZ8Q","profile":{"id":"profile_123","email":"Foo@Example.com"}}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		scenario       string
+		normalizeEmail bool
+		expected       string
+	}{
+		{scenario: "email is untouched when disabled", expected: "Foo@Example.com"},
+		{scenario: "email is lowercased when enabled", normalizeEmail: true, expected: "foo@example.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			client := &Client{
+				APIKey:         "test",
+				ClientID:       "client_123",
+				Endpoint:       server.URL,
+				HTTPClient:     server.Client(),
+				NormalizeEmail: test.normalizeEmail,
+			}
+
+			profileAndToken, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{
+				Code: "authorization_code",
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, profileAndToken.Profile.Email)
+
+			profile, err := client.GetProfile(context.Background(), GetProfileOpts{
+				AccessToken: "01DMEK0J53CVMC32CK5SE0KZ8Q",
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, profile.Email)
+		})
+	}
+}
+
 func TestClientGetProfile(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -497,3 +816,970 @@ func listConnectionsTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(body)
 }
+
+func TestGetConnectionByOrganization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := ListConnectionsResponse{Data: []Connection{}}
+		if r.URL.Query().Get("organization_id") == "org_123" {
+			response.Data = append(response.Data,
+				Connection{ID: "conn_draft", OrganizationID: "org_123", State: Draft},
+				Connection{ID: "conn_active", OrganizationID: "org_123", State: Active},
+			)
+		}
+		if r.URL.Query().Get("organization_id") == "org_inactive" {
+			response.Data = append(response.Data,
+				Connection{ID: "conn_inactive", OrganizationID: "org_inactive", State: Inactive},
+			)
+		}
+
+		body, _ := json.Marshal(response)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	t.Run("GetConnectionByOrganization prefers active Connections", func(t *testing.T) {
+		connection, found, err := client.GetConnectionByOrganization(context.Background(), GetConnectionByOrganizationOpts{
+			OrganizationID: "org_123",
+		})
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "conn_active", connection.ID)
+	})
+
+	t.Run("GetConnectionByOrganization falls back to the first Connection", func(t *testing.T) {
+		connection, found, err := client.GetConnectionByOrganization(context.Background(), GetConnectionByOrganizationOpts{
+			OrganizationID: "org_inactive",
+		})
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "conn_inactive", connection.ID)
+	})
+
+	t.Run("GetConnectionByOrganization returns not found when the Organization has no Connection", func(t *testing.T) {
+		connection, found, err := client.GetConnectionByOrganization(context.Background(), GetConnectionByOrganizationOpts{
+			OrganizationID: "org_unknown",
+		})
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Equal(t, Connection{}, connection)
+	})
+
+	t.Run("GetConnectionByOrganization requires an Organization", func(t *testing.T) {
+		_, _, err := client.GetConnectionByOrganization(context.Background(), GetConnectionByOrganizationOpts{})
+		require.Error(t, err)
+	})
+}
+
+func TestUpdateConnection(t *testing.T) {
+	tests := []struct {
+		scenario string
+		client   *Client
+		options  UpdateConnectionOpts
+		expected Connection
+		err      bool
+	}{
+		{
+			scenario: "Request without API Key returns an error",
+			client:   &Client{},
+			options: UpdateConnectionOpts{
+				Connection: "conn_id",
+			},
+			err: true,
+		},
+		{
+			scenario: "Request without Connection returns an error",
+			client: &Client{
+				APIKey: "test",
+			},
+			options: UpdateConnectionOpts{
+				Name: "Foo Corp",
+			},
+			err: true,
+		},
+		{
+			scenario: "Request returns the updated Connection",
+			client: &Client{
+				APIKey: "test",
+			},
+			options: UpdateConnectionOpts{
+				Connection: "conn_id",
+				Name:       "Bar Corp",
+				Domains:    []string{"bar.com"},
+			},
+			expected: Connection{
+				ID:             "conn_id",
+				ConnectionType: "OktaSAML",
+				State:          Active,
+				Name:           "Bar Corp",
+				Domains: []ConnectionDomain{
+					{ID: "conn_domain_1", Domain: "bar.com"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(updateConnectionTestHandler))
+			defer server.Close()
+
+			client := test.client
+			client.Endpoint = server.URL
+			client.HTTPClient = server.Client()
+
+			connection, err := client.UpdateConnection(context.Background(), test.options)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, connection)
+		})
+	}
+}
+
+func updateConnectionTestHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if auth != "Bearer test" {
+		http.Error(w, "bad auth", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPut || r.URL.Path != "/connections/conn_id" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var opts struct {
+		Name    string   `json:"name"`
+		Domains []string `json:"domains"`
+	}
+	json.NewDecoder(r.Body).Decode(&opts)
+
+	connection := Connection{
+		ID:             "conn_id",
+		ConnectionType: "OktaSAML",
+		State:          Active,
+		Name:           opts.Name,
+	}
+	for i, domain := range opts.Domains {
+		connection.Domains = append(connection.Domains, ConnectionDomain{
+			ID:     fmt.Sprintf("conn_domain_%d", i+1),
+			Domain: domain,
+		})
+	}
+
+	body, err := json.Marshal(connection)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func TestConnectionDomains(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		connection := Connection{
+			ID: "conn_id",
+			Domains: []ConnectionDomain{
+				{ID: "conn_domain_1", Domain: "foo-corp.com"},
+			},
+		}
+
+		if r.Method == http.MethodPut {
+			var opts struct {
+				Domains []string `json:"domains"`
+			}
+			json.NewDecoder(r.Body).Decode(&opts)
+			sent = opts.Domains
+
+			connection.Domains = nil
+			for i, domain := range opts.Domains {
+				connection.Domains = append(connection.Domains, ConnectionDomain{
+					ID:     fmt.Sprintf("conn_domain_%d", i+1),
+					Domain: domain,
+				})
+			}
+		}
+
+		body, _ := json.Marshal(connection)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	t.Run("AddConnectionDomain adds the domain", func(t *testing.T) {
+		connection, err := client.AddConnectionDomain(context.Background(), ConnectionDomainOpts{
+			Connection: "conn_id",
+			Domain:     "foo-corp.io",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"foo-corp.com", "foo-corp.io"}, sent)
+		require.Len(t, connection.Domains, 2)
+	})
+
+	t.Run("AddConnectionDomain keeps existing domains once", func(t *testing.T) {
+		_, err := client.AddConnectionDomain(context.Background(), ConnectionDomainOpts{
+			Connection: "conn_id",
+			Domain:     "Foo-Corp.com",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"foo-corp.com"}, sent)
+	})
+
+	t.Run("RemoveConnectionDomain removes the last domain", func(t *testing.T) {
+		connection, err := client.RemoveConnectionDomain(context.Background(), ConnectionDomainOpts{
+			Connection: "conn_id",
+			Domain:     "foo-corp.com",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{}, sent)
+		require.Empty(t, connection.Domains)
+	})
+
+	t.Run("invalid domains are rejected", func(t *testing.T) {
+		for _, domain := range []string{"", "foo-corp", "-foo.com", "foo corp.com", "https://foo-corp.com", "foo-corp.com/"} {
+			_, err := client.AddConnectionDomain(context.Background(), ConnectionDomainOpts{
+				Connection: "conn_id",
+				Domain:     domain,
+			})
+			require.Error(t, err, domain)
+		}
+	})
+
+	t.Run("Connection is required", func(t *testing.T) {
+		_, err := client.RemoveConnectionDomain(context.Background(), ConnectionDomainOpts{Domain: "foo-corp.com"})
+		require.Error(t, err)
+	})
+}
+
+func TestCreateConnection(t *testing.T) {
+	tests := []struct {
+		scenario string
+		client   *Client
+		options  CreateConnectionOpts
+		expected Connection
+		err      bool
+	}{
+		{
+			scenario: "Request without API Key returns an error",
+			client:   &Client{},
+			options: CreateConnectionOpts{
+				ConnectionType:  OktaSAML,
+				OrganizationID:  "org_id",
+				SAMLMetadataURL: "https://foo-corp.okta.com/app/metadata",
+			},
+			err: true,
+		},
+		{
+			scenario: "Request without metadata for a SAML Connection returns an error",
+			client: &Client{
+				APIKey: "test",
+			},
+			options: CreateConnectionOpts{
+				ConnectionType: OktaSAML,
+				OrganizationID: "org_id",
+			},
+			err: true,
+		},
+		{
+			scenario: "Request with a metadata URL returns the Connection",
+			client: &Client{
+				APIKey: "test",
+			},
+			options: CreateConnectionOpts{
+				Name:            "Foo Corp",
+				ConnectionType:  OktaSAML,
+				OrganizationID:  "org_id",
+				SAMLMetadataURL: "https://foo-corp.okta.com/app/metadata",
+			},
+			expected: Connection{
+				ID:             "conn_id",
+				Name:           "Foo Corp",
+				ConnectionType: OktaSAML,
+				OrganizationID: "org_id",
+				State:          Active,
+			},
+		},
+		{
+			scenario: "Request with inline metadata XML returns the Connection",
+			client: &Client{
+				APIKey: "test",
+			},
+			options: CreateConnectionOpts{
+				Name:            "Foo Corp",
+				ConnectionType:  GenericSAML,
+				OrganizationID:  "org_id",
+				SAMLMetadataXML: "<EntityDescriptor></EntityDescriptor>",
+			},
+			expected: Connection{
+				ID:             "conn_id",
+				Name:           "Foo Corp",
+				ConnectionType: GenericSAML,
+				OrganizationID: "org_id",
+				State:          Active,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(createConnectionTestHandler))
+			defer server.Close()
+
+			client := test.client
+			client.Endpoint = server.URL
+			client.HTTPClient = server.Client()
+
+			connection, err := client.CreateConnection(context.Background(), test.options)
+			if test.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, connection)
+		})
+	}
+}
+
+func createConnectionTestHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if auth != "Bearer test" {
+		http.Error(w, "bad auth", http.StatusUnauthorized)
+		return
+	}
+
+	if r.Method != http.MethodPost || r.URL.Path != "/connections" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var opts CreateConnectionOpts
+	json.NewDecoder(r.Body).Decode(&opts)
+
+	if opts.SAMLMetadataURL == "" && opts.SAMLMetadataXML == "" {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+
+	body, err := json.Marshal(Connection{
+		ID:             "conn_id",
+		Name:           opts.Name,
+		ConnectionType: opts.ConnectionType,
+		OrganizationID: opts.OrganizationID,
+		State:          Active,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(body)
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("options are applied", func(t *testing.T) {
+		logger := log.New(ioutil.Discard, "", 0)
+		httpClient := &http.Client{}
+		client := NewClient(
+			"test",
+			"client_123",
+			WithTimeout(time.Minute),
+			WithHTTPClient(httpClient),
+			WithEndpoint("https://auth.example.com"),
+			WithLogger(logger),
+		)
+
+		require.Equal(t, "test", client.APIKey)
+		require.Equal(t, "client_123", client.ClientID)
+		require.Equal(t, "https://auth.example.com", client.Endpoint)
+		require.Equal(t, time.Minute, client.HTTPClient.Timeout)
+		require.Zero(t, httpClient.Timeout)
+		require.Equal(t, logger, client.Logger)
+	})
+
+	t.Run("timeout creates an http.Client when none is given", func(t *testing.T) {
+		client := NewClient("test", "client_123", WithTimeout(time.Minute))
+
+		require.Equal(t, time.Minute, client.HTTPClient.Timeout)
+		require.NotNil(t, client.HTTPClient.Transport)
+	})
+
+	t.Run("defaults are applied on first use", func(t *testing.T) {
+		client := NewClient("test", "client_123")
+
+		u, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+			Connection:  "connection_123",
+			RedirectURI: "https://example.com/sso/workos/callback",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "api.workos.com", u.Host)
+		require.Equal(t, 15*time.Second, client.HTTPClient.Timeout)
+	})
+
+	t.Run("default timeout can be disabled", func(t *testing.T) {
+		client := NewClient("test", "client_123")
+		client.NoDefaultTimeout = true
+
+		_, err := client.GetAuthorizationURL(GetAuthorizationURLOpts{
+			Connection:  "connection_123",
+			RedirectURI: "https://example.com/sso/workos/callback",
+		})
+		require.NoError(t, err)
+		require.Zero(t, client.HTTPClient.Timeout)
+	})
+}
+
+func TestConnectionUnmarshalJSONTimestamps(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     string
+		expected Connection
+	}{
+		{
+			scenario: "valid timestamps are decoded",
+			data:     `{"id":"conn_123","created_at":"2021-06-25T19:07:33.155Z","updated_at":"2021-06-26T19:07:33.155Z"}`,
+			expected: Connection{
+				ID:        "conn_123",
+				CreatedAt: time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt: time.Date(2021, time.June, 26, 19, 7, 33, 155000000, time.UTC),
+			},
+		},
+		{
+			scenario: "empty timestamps are decoded as the zero time",
+			data:     `{"id":"conn_123","created_at":"","updated_at":""}`,
+			expected: Connection{ID: "conn_123"},
+		},
+		{
+			scenario: "null timestamps are decoded as the zero time",
+			data:     `{"id":"conn_123","created_at":null,"updated_at":null}`,
+			expected: Connection{ID: "conn_123"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var v Connection
+			err := json.Unmarshal([]byte(test.data), &v)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestProfileEmailDomain(t *testing.T) {
+	require.Equal(t, "foo-corp.com", Profile{Email: "Marcelina+sso@Foo-Corp.com"}.EmailDomain())
+	require.Equal(t, "eu.foo-corp.com", Profile{Email: "marcelina@eu.foo-corp.com"}.EmailDomain())
+	require.Empty(t, Profile{Email: "marcelina"}.EmailDomain())
+}
+
+func TestProfileFullName(t *testing.T) {
+	require.Equal(t, "marcelina", Profile{Email: "marcelina@foo-corp.com"}.FullName())
+	require.Equal(t, "Marcelina", Profile{FirstName: "Marcelina", Email: "marcelina@foo-corp.com"}.FullName())
+	require.Equal(t, "Marcelina Davis", Profile{FirstName: "Marcelina", LastName: "Davis", Email: "marcelina@foo-corp.com"}.FullName())
+}
+
+func TestProfileAndTokenUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     string
+		expected ProfileAndToken
+	}{
+		{
+			scenario: "connection and organization IDs are decoded",
+			data: `{
+				"access_token": "01DVX6QBS3EG6FHY2ESAA5Q65X",
+				"profile": {
+					"id": "prof_123",
+					"idp_id": "123",
+					"organization_id": "org_123",
+					"connection_id": "conn_123",
+					"connection_type": "OktaSAML",
+					"email": "foo@test.com"
+				}
+			}`,
+			expected: ProfileAndToken{
+				AccessToken: "01DVX6QBS3EG6FHY2ESAA5Q65X",
+				Profile: Profile{
+					ID:             "prof_123",
+					IdpID:          "123",
+					OrganizationID: "org_123",
+					ConnectionID:   "conn_123",
+					ConnectionType: OktaSAML,
+					Email:          "foo@test.com",
+				},
+			},
+		},
+		{
+			scenario: "missing IDs are left empty",
+			data: `{
+				"access_token": "01DVX6QBS3EG6FHY2ESAA5Q65X",
+				"profile": {"id": "prof_123", "connection_type": "OktaSAML", "email": "foo@test.com"}
+			}`,
+			expected: ProfileAndToken{
+				AccessToken: "01DVX6QBS3EG6FHY2ESAA5Q65X",
+				Profile: Profile{
+					ID:             "prof_123",
+					ConnectionType: OktaSAML,
+					Email:          "foo@test.com",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var v ProfileAndToken
+			err := json.Unmarshal([]byte(test.data), &v)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestClientGetProfileTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+		ClientID:   "client_123",
+	}
+
+	t.Run("GetProfileAndToken is canceled by its timeout", func(t *testing.T) {
+		_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{
+			Code:    "authorization_code",
+			Timeout: 10 * time.Millisecond,
+		})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("GetProfile is canceled by its timeout", func(t *testing.T) {
+		_, err := client.GetProfile(context.Background(), GetProfileOpts{
+			AccessToken: "access_token",
+			Timeout:     10 * time.Millisecond,
+		})
+		require.Error(t, err)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("a zero timeout does not bound the call", func(t *testing.T) {
+		_, err := client.GetProfile(context.Background(), GetProfileOpts{
+			AccessToken: "access_token",
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestClientClose(t *testing.T) {
+	var closed int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(profileTestHandler))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			atomic.StoreInt32(&closed, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := &Client{
+		Endpoint: server.URL,
+		APIKey:   "test",
+	}
+
+	_, err := client.GetProfile(context.Background(), GetProfileOpts{AccessToken: "access_token"})
+	require.NoError(t, err)
+	require.Zero(t, atomic.LoadInt32(&closed))
+
+	client.Close()
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&closed) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestLogout(t *testing.T) {
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sso/logout/authorize" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"logout_url":"https://api.workos.com/sso/logout?token=logout_token_123","logout_token":"logout_token_123"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	t.Run("AuthorizeLogout returns the logout token", func(t *testing.T) {
+		authorization, err := client.AuthorizeLogout(context.Background(), AuthorizeLogoutOpts{
+			ProfileID: "prof_123",
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"profile_id": "prof_123"}, payload)
+		require.Equal(t, LogoutAuthorization{
+			LogoutURL:   "https://api.workos.com/sso/logout?token=logout_token_123",
+			LogoutToken: "logout_token_123",
+		}, authorization)
+	})
+
+	t.Run("AuthorizeLogout requires a ProfileID", func(t *testing.T) {
+		_, err := client.AuthorizeLogout(context.Background(), AuthorizeLogoutOpts{})
+		require.Error(t, err)
+	})
+
+	t.Run("GetLogoutURL builds the logout URL", func(t *testing.T) {
+		u, err := client.GetLogoutURL(GetLogoutURLOpts{Token: "logout_token_123"})
+		require.NoError(t, err)
+		require.Equal(t, server.URL+"/sso/logout?token=logout_token_123", u.String())
+
+		_, err = client.GetLogoutURL(GetLogoutURLOpts{})
+		require.Error(t, err)
+	})
+}
+
+func TestListConnectionsLimit(t *testing.T) {
+	var limit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit = r.URL.Query().Get("limit")
+		w.Write([]byte(`{"data":[],"listMetadata":{}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	_, err := client.ListConnections(context.Background(), ListConnectionsOpts{Limit: 500})
+	require.NoError(t, err)
+	require.Equal(t, "100", limit)
+}
+
+func TestListConnectionsState(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"data":[{"id":"conn_id","state":"inactive","organization_id":"org_123"}],"listMetadata":{}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	res, err := client.ListConnections(context.Background(), ListConnectionsOpts{
+		OrganizationID: "org_123",
+		Domain:         "foo-corp.com",
+		State:          Inactive,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "inactive", query.Get("state"))
+	require.Equal(t, "org_123", query.Get("organization_id"))
+	require.Equal(t, "foo-corp.com", query.Get("domain"))
+	require.Len(t, res.Data, 1)
+	require.Equal(t, Inactive, res.Data[0].State)
+}
+
+func TestClientMissingAPIKey(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		ClientID:   "client_123",
+	}
+
+	_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+
+	_, err = client.GetConnection(context.Background(), GetConnectionOpts{Connection: "connection_id"})
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+
+	_, err = client.ListConnections(context.Background(), ListConnectionsOpts{})
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+
+	require.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestClientProfileIncludeRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/sso/profile" {
+			w.Write([]byte(`{"id":"prof_123","email":"foo@test.com"}`))
+			return
+		}
+		w.Write([]byte(`{"access_token":"token","profile":{"id":"prof_123","email":"foo@test.com"}}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+		ClientID:   "client_123",
+	}
+
+	t.Run("GetProfileAndToken", func(t *testing.T) {
+		res, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "code"})
+		require.NoError(t, err)
+		require.Nil(t, res.Profile.Raw)
+
+		res, err = client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "code", IncludeRaw: true})
+		require.NoError(t, err)
+		require.Equal(t, "prof_123", res.Profile.ID)
+		require.JSONEq(t, `{"id":"prof_123","email":"foo@test.com"}`, string(res.Profile.Raw))
+	})
+
+	t.Run("GetProfile", func(t *testing.T) {
+		profile, err := client.GetProfile(context.Background(), GetProfileOpts{AccessToken: "token"})
+		require.NoError(t, err)
+		require.Nil(t, profile.Raw)
+
+		profile, err = client.GetProfile(context.Background(), GetProfileOpts{AccessToken: "token", IncludeRaw: true})
+		require.NoError(t, err)
+		require.Equal(t, "prof_123", profile.ID)
+		require.JSONEq(t, `{"id":"prof_123","email":"foo@test.com"}`, string(profile.Raw))
+	})
+}
+
+func TestClientNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	_, err := client.GetConnection(context.Background(), GetConnectionOpts{Connection: "conn_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+
+	err = client.DeleteConnection(context.Background(), DeleteConnectionOpts{Connection: "conn_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+}
+
+func TestClientEndpointTrailingSlash(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"id":"conn_123"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL + "//",
+		APIKey:     "test",
+	}
+
+	_, err := client.GetConnection(context.Background(), GetConnectionOpts{Connection: "conn_123"})
+	require.NoError(t, err)
+	require.Equal(t, "/connections/conn_123", path)
+}
+
+func TestClientTokenExchangeRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(profileAndTokenTestHandler))
+	defer server.Close()
+
+	// newHTTPClient returns a client failing to dial the server the first
+	// time, before any request is sent.
+	newHTTPClient := func(dials *int32) *http.Client {
+		var dialer net.Dialer
+		return &http.Client{Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				if atomic.AddInt32(dials, 1) == 1 {
+					return nil, &net.OpError{Op: "dial", Net: network, Err: errors.New("connection refused")}
+				}
+				return dialer.DialContext(ctx, network, addr)
+			},
+		}}
+	}
+
+	t.Run("dial errors are retried", func(t *testing.T) {
+		var dials int32
+		client := &Client{
+			HTTPClient:           newHTTPClient(&dials),
+			Endpoint:             server.URL,
+			APIKey:               "test",
+			ClientID:             "client_123",
+			TokenExchangeRetries: 1,
+		}
+
+		res, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+		require.NoError(t, err)
+		require.Equal(t, "profile_123", res.Profile.ID)
+		require.Equal(t, int32(2), atomic.LoadInt32(&dials))
+	})
+
+	t.Run("retries are disabled by default", func(t *testing.T) {
+		var dials int32
+		client := &Client{
+			HTTPClient: newHTTPClient(&dials),
+			Endpoint:   server.URL,
+			APIKey:     "test",
+			ClientID:   "client_123",
+		}
+
+		_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(&dials))
+	})
+
+	t.Run("connections closed after the request was sent are not retried", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+		}))
+		defer server.Close()
+
+		client := &Client{
+			HTTPClient:           server.Client(),
+			Endpoint:             server.URL,
+			APIKey:               "test",
+			ClientID:             "client_123",
+			TokenExchangeRetries: 2,
+		}
+
+		_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("HTTP errors are not retried", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		client := &Client{
+			HTTPClient:           server.Client(),
+			Endpoint:             server.URL,
+			APIKey:               "test",
+			ClientID:             "client_123",
+			TokenExchangeRetries: 2,
+		}
+
+		_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+		require.Error(t, err)
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestClientGetTokenResponse(t *testing.T) {
+	data := `{
+		"access_token": "01DMEK0J53CVMC32CK5SE0KZ8Q",
+		"expires_in": 600,
+		"token_type": "bearer",
+		"profile": {"id": "prof_123", "email": "foo@test.com"}
+	}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(data))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+		ClientID:   "client_123",
+	}
+
+	res, err := client.GetTokenResponse(context.Background(), GetProfileAndTokenOpts{Code: "code"})
+	require.NoError(t, err)
+	require.Equal(t, "01DMEK0J53CVMC32CK5SE0KZ8Q", res.AccessToken)
+	require.Equal(t, 600, res.ExpiresIn)
+	require.Equal(t, Profile{ID: "prof_123", Email: "foo@test.com"}, res.Profile)
+	require.JSONEq(t, data, string(res.RawResponse))
+
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(res.RawResponse, &raw))
+	require.Equal(t, "bearer", raw["token_type"])
+
+	profileAndToken, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "code"})
+	require.NoError(t, err)
+	require.Equal(t, ProfileAndToken{AccessToken: res.AccessToken, Profile: res.Profile}, profileAndToken)
+}
+
+func TestConnectionTypeRoundtrip(t *testing.T) {
+	types := []ConnectionType{
+		AppleOAuth,
+		EntraIdOIDC,
+		GenericOIDC,
+		GitHubOAuth,
+		JumpCloudSAML,
+		MicrosoftOAuth,
+		OktaOIDC,
+		OneLoginSAML,
+		ConnectionType("SomeFutureSAML"),
+	}
+
+	for _, connectionType := range types {
+		t.Run(string(connectionType), func(t *testing.T) {
+			data, err := json.Marshal(Connection{ID: "conn_id", ConnectionType: connectionType})
+			require.NoError(t, err)
+
+			var connection Connection
+			require.NoError(t, json.Unmarshal(data, &connection))
+			require.Equal(t, connectionType, connection.ConnectionType)
+		})
+	}
+}
+
+func TestListConnectionsOptsPages(t *testing.T) {
+	opts := ListConnectionsOpts{OrganizationID: "org_123", Limit: 5, Order: Asc, After: "conn_1"}
+
+	next := opts.NextPage(common.ListMetadata{Before: "conn_2", After: "conn_6"})
+	expected := opts
+	expected.After = "conn_6"
+	require.Equal(t, expected, next)
+
+	prev := next.PrevPage(common.ListMetadata{Before: "conn_7", After: "conn_11"})
+	expected.Before = "conn_7"
+	expected.After = ""
+	require.Equal(t, expected, prev)
+}
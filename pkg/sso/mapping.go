@@ -0,0 +1,62 @@
+package sso
+
+import (
+	"fmt"
+	"sort"
+)
+
+// AttributeMapping describes which raw IdP attributes hold the values of the
+// normalized Profile fields. An empty list means that no raw attribute holds
+// the value of the field, which usually points to a missing attribute mapping
+// in the IdP.
+type AttributeMapping struct {
+	IdpID     []string
+	Email     []string
+	FirstName []string
+	LastName  []string
+}
+
+// AttributeMapping infers the mapping between the raw IdP attributes and the
+// normalized fields of the Profile by comparing their values. WorkOS doesn't
+// expose the mapping of a Connection, so this is meant as a debugging aid.
+func (p Profile) AttributeMapping() AttributeMapping {
+	return AttributeMapping{
+		IdpID:     p.rawAttributesMatching(p.IdpID),
+		Email:     p.rawAttributesMatching(p.Email),
+		FirstName: p.rawAttributesMatching(p.FirstName),
+		LastName:  p.rawAttributesMatching(p.LastName),
+	}
+}
+
+func (p Profile) rawAttributesMatching(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	var names []string
+	for name, raw := range p.RawAttributes {
+		if rawAttributeHolds(raw, value) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func rawAttributeHolds(raw interface{}, value string) bool {
+	switch raw := raw.(type) {
+	case nil:
+		return false
+	case []interface{}:
+		for _, v := range raw {
+			if rawAttributeHolds(v, value) {
+				return true
+			}
+		}
+		return false
+	case map[string]interface{}:
+		return false
+	default:
+		return fmt.Sprint(raw) == value
+	}
+}
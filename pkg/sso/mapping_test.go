@@ -0,0 +1,35 @@
+package sso
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileAttributeMapping(t *testing.T) {
+	data := `{
+		"id": "profile_123",
+		"idp_id": "123",
+		"email": "marcelina@foo-corp.com",
+		"first_name": "Marcelina",
+		"last_name": "",
+		"raw_attributes": {
+			"id": 123,
+			"email": "marcelina@foo-corp.com",
+			"emails": ["marcelina@foo-corp.com", "marce@foo-corp.com"],
+			"given_name": "Marcelina",
+			"surname": null,
+			"address": {"city": "Marcelina"}
+		}
+	}`
+
+	var profile Profile
+	require.NoError(t, json.Unmarshal([]byte(data), &profile))
+
+	require.Equal(t, AttributeMapping{
+		IdpID:     []string{"id"},
+		Email:     []string{"email", "emails"},
+		FirstName: []string{"given_name"},
+	}, profile.AttributeMapping())
+}
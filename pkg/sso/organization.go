@@ -0,0 +1,20 @@
+package sso
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrOrganizationMismatch is returned when the Profile of an authenticated
+// user does not belong to the expected Organization, e.g. when a login
+// through the Connection of another tenant is attempted.
+var ErrOrganizationMismatch = errors.New("profile organization mismatch")
+
+// checkOrganization returns an error wrapping ErrOrganizationMismatch when an
+// Organization is expected and the Profile belongs to another one.
+func checkOrganization(expected string, p Profile) error {
+	if expected == "" || p.OrganizationID == expected {
+		return nil
+	}
+	return fmt.Errorf("%w: expected %q, got %q", ErrOrganizationMismatch, expected, p.OrganizationID)
+}
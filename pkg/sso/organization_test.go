@@ -0,0 +1,63 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectedOrganizationID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profile := `{"id":"prof_123","organization_id":"org_123","connection_id":"conn_123","email":"marcelina@foo-corp.com"}`
+		if r.URL.Path == "/sso/profile" {
+			w.Write([]byte(profile))
+			return
+		}
+		w.Write([]byte(`{"access_token":"access_token","profile":` + profile + `}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		ClientID:   "client_123",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	t.Run("matching organization is accepted", func(t *testing.T) {
+		res, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{
+			Code:                   "authorization_code",
+			ExpectedOrganizationID: "org_123",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "prof_123", res.Profile.ID)
+
+		profile, err := client.GetProfile(context.Background(), GetProfileOpts{
+			AccessToken:            "access_token",
+			ExpectedOrganizationID: "org_123",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "prof_123", profile.ID)
+	})
+
+	t.Run("mismatched organization is rejected", func(t *testing.T) {
+		res, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{
+			Code:                   "authorization_code",
+			ExpectedOrganizationID: "org_456",
+		})
+		require.True(t, errors.Is(err, ErrOrganizationMismatch))
+		require.EqualError(t, err, `profile organization mismatch: expected "org_456", got "org_123"`)
+		require.Equal(t, ProfileAndToken{}, res)
+
+		profile, err := client.GetProfile(context.Background(), GetProfileOpts{
+			AccessToken:            "access_token",
+			ExpectedOrganizationID: "org_456",
+		})
+		require.True(t, errors.Is(err, ErrOrganizationMismatch))
+		require.Equal(t, Profile{}, profile)
+	})
+}
@@ -27,12 +27,29 @@ func GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
 	return DefaultClient.GetAuthorizationURL(opts)
 }
 
+// GetAuthorizationURLs returns the authorization urls generated with each of
+// the given options.
+func GetAuthorizationURLs(opts []GetAuthorizationURLOpts) ([]*url.URL, error) {
+	return DefaultClient.GetAuthorizationURLs(opts)
+}
+
+// GetAuthorizationURLContext is like GetAuthorizationURL but takes a context.
+func GetAuthorizationURLContext(ctx context.Context, opts GetAuthorizationURLOpts) (*url.URL, error) {
+	return DefaultClient.GetAuthorizationURLContext(ctx, opts)
+}
+
 // GetProfileAndToken returns a profile describing the user that authenticated with
 // WorkOS SSO.
 func GetProfileAndToken(ctx context.Context, opts GetProfileAndTokenOpts) (ProfileAndToken, error) {
 	return DefaultClient.GetProfileAndToken(ctx, opts)
 }
 
+// GetTokenResponse returns the full response of the WorkOS SSO token
+// endpoint.
+func GetTokenResponse(ctx context.Context, opts GetProfileAndTokenOpts) (TokenResponse, error) {
+	return DefaultClient.GetTokenResponse(ctx, opts)
+}
+
 // GetProfile returns a profile describing the user that authenticated with
 // WorkOS SSO.
 func GetProfile(ctx context.Context, opts GetProfileOpts) (Profile, error) {
@@ -53,6 +70,14 @@ func GetConnection(
 	return DefaultClient.GetConnection(ctx, opts)
 }
 
+// ValidateConnection checks the configuration of a Connection.
+func ValidateConnection(
+	ctx context.Context,
+	opts ValidateConnectionOpts,
+) (ValidationResult, error) {
+	return DefaultClient.ValidateConnection(ctx, opts)
+}
+
 // ListConnections gets a list of existing Connections.
 func ListConnections(
 	ctx context.Context,
@@ -61,6 +86,59 @@ func ListConnections(
 	return DefaultClient.ListConnections(ctx, opts)
 }
 
+// GetConnectionByOrganization returns the Connection of an Organization.
+func GetConnectionByOrganization(
+	ctx context.Context,
+	opts GetConnectionByOrganizationOpts,
+) (Connection, bool, error) {
+	return DefaultClient.GetConnectionByOrganization(ctx, opts)
+}
+
+// UpdateConnection updates a Connection.
+func UpdateConnection(
+	ctx context.Context,
+	opts UpdateConnectionOpts,
+) (Connection, error) {
+	return DefaultClient.UpdateConnection(ctx, opts)
+}
+
+// AddConnectionDomain adds a domain to a Connection.
+func AddConnectionDomain(
+	ctx context.Context,
+	opts ConnectionDomainOpts,
+) (Connection, error) {
+	return DefaultClient.AddConnectionDomain(ctx, opts)
+}
+
+// RemoveConnectionDomain removes a domain from a Connection.
+func RemoveConnectionDomain(
+	ctx context.Context,
+	opts ConnectionDomainOpts,
+) (Connection, error) {
+	return DefaultClient.RemoveConnectionDomain(ctx, opts)
+}
+
+// CreateConnection creates a Connection.
+func CreateConnection(
+	ctx context.Context,
+	opts CreateConnectionOpts,
+) (Connection, error) {
+	return DefaultClient.CreateConnection(ctx, opts)
+}
+
+// AuthorizeLogout authorizes the logout of a user signed in with SSO.
+func AuthorizeLogout(
+	ctx context.Context,
+	opts AuthorizeLogoutOpts,
+) (LogoutAuthorization, error) {
+	return DefaultClient.AuthorizeLogout(ctx, opts)
+}
+
+// GetLogoutURL returns the URL to redirect the user to in order to log out.
+func GetLogoutURL(opts GetLogoutURLOpts) (*url.URL, error) {
+	return DefaultClient.GetLogoutURL(opts)
+}
+
 // DeleteConnection deletes a Connection.
 func DeleteConnection(
 	ctx context.Context,
@@ -0,0 +1,89 @@
+package sso
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// SignedStateTTL is the duration for which a state created with
+// NewSignedState is valid.
+const SignedStateTTL = 10 * time.Minute
+
+var (
+	// ErrInvalidState is returned when a signed state is malformed or its
+	// signature doesn't match.
+	ErrInvalidState = errors.New("invalid state")
+
+	// ErrExpiredState is returned when a signed state is older than
+	// SignedStateTTL.
+	ErrExpiredState = errors.New("expired state")
+)
+
+// now is the clock used to sign and verify states and to expire cached
+// profiles.
+var now = time.Now
+
+type signedState struct {
+	Payload   map[string]string `json:"p"`
+	ExpiresAt int64             `json:"exp"`
+}
+
+// NewSignedState returns a tamper-proof value to use as the State of an
+// authorization URL. The payload is signed with the secret using HMAC-SHA256
+// and expires after SignedStateTTL. It is not encrypted.
+func NewSignedState(payload map[string]string, secret string) (string, error) {
+	if secret == "" {
+		return "", errors.New("incomplete arguments: missing secret")
+	}
+
+	data, err := json.Marshal(signedState{
+		Payload:   payload,
+		ExpiresAt: now().Add(SignedStateTTL).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + signState(encoded, secret), nil
+}
+
+// VerifySignedState checks the signature and the expiry of a state created
+// with NewSignedState and returns its payload.
+func VerifySignedState(state, secret string) (map[string]string, error) {
+	parts := strings.Split(state, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidState
+	}
+
+	if !hmac.Equal([]byte(parts[1]), []byte(signState(parts[0], secret))) {
+		return nil, ErrInvalidState
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidState
+	}
+
+	var s signedState
+	if err = json.Unmarshal(data, &s); err != nil {
+		return nil, ErrInvalidState
+	}
+
+	if now().Unix() > s.ExpiresAt {
+		return nil, ErrExpiredState
+	}
+
+	return s.Payload, nil
+}
+
+func signState(encoded, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,70 @@
+package sso
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedState(t *testing.T) {
+	payload := map[string]string{"redirect_to": "/dashboard", "nonce": "abc123"}
+
+	t.Run("state round-trips its payload", func(t *testing.T) {
+		state, err := NewSignedState(payload, "secret")
+		require.NoError(t, err)
+
+		verified, err := VerifySignedState(state, "secret")
+		require.NoError(t, err)
+		require.Equal(t, payload, verified)
+	})
+
+	t.Run("tampered payload is rejected", func(t *testing.T) {
+		state, err := NewSignedState(payload, "secret")
+		require.NoError(t, err)
+
+		forged, err := NewSignedState(map[string]string{"redirect_to": "https://evil.com"}, "other")
+		require.NoError(t, err)
+
+		tampered := strings.Split(forged, ".")[0] + "." + strings.Split(state, ".")[1]
+		_, err = VerifySignedState(tampered, "secret")
+		require.Equal(t, ErrInvalidState, err)
+	})
+
+	t.Run("wrong secret is rejected", func(t *testing.T) {
+		state, err := NewSignedState(payload, "secret")
+		require.NoError(t, err)
+
+		_, err = VerifySignedState(state, "other")
+		require.Equal(t, ErrInvalidState, err)
+	})
+
+	t.Run("malformed state is rejected", func(t *testing.T) {
+		_, err := VerifySignedState("not-a-state", "secret")
+		require.Equal(t, ErrInvalidState, err)
+	})
+
+	t.Run("expired state is rejected", func(t *testing.T) {
+		defer func(f func() time.Time) { now = f }(now)
+
+		issuedAt := time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC)
+		now = func() time.Time { return issuedAt }
+
+		state, err := NewSignedState(payload, "secret")
+		require.NoError(t, err)
+
+		now = func() time.Time { return issuedAt.Add(SignedStateTTL - time.Second) }
+		_, err = VerifySignedState(state, "secret")
+		require.NoError(t, err)
+
+		now = func() time.Time { return issuedAt.Add(SignedStateTTL + time.Second) }
+		_, err = VerifySignedState(state, "secret")
+		require.Equal(t, ErrExpiredState, err)
+	})
+
+	t.Run("secret is required", func(t *testing.T) {
+		_, err := NewSignedState(payload, "")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,44 @@
+package sso
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrCodeAlreadyUsed is returned by GetProfileAndToken when the UsedCodeStore
+// of the Client reports that the authorization code was already exchanged.
+var ErrCodeAlreadyUsed = errors.New("authorization code was already used")
+
+// UsedCodeStore records the authorization codes exchanged by
+// GetProfileAndToken so that a replayed code is rejected without calling
+// WorkOS. Implementations backed by a shared store such as Redis provide
+// replay protection across the instances of a horizontally scaled service.
+type UsedCodeStore interface {
+	// Mark atomically records the code as used, e.g. with SETNX. It must
+	// return ErrCodeAlreadyUsed when the code was already marked, so that
+	// concurrent exchanges of the same code cannot both succeed.
+	Mark(ctx context.Context, code string) error
+
+	// Unmark removes a code marked with Mark. It is called when the exchange
+	// of the code failed, so that the code can be exchanged again.
+	Unmark(ctx context.Context, code string) error
+}
+
+// markCodeUsed marks the given code as used, returning ErrCodeAlreadyUsed
+// when it already was. It is a no-op when the Client has no UsedCodeStore.
+func (c *Client) markCodeUsed(ctx context.Context, code string) error {
+	if c.UsedCodes == nil {
+		return nil
+	}
+	return c.UsedCodes.Mark(ctx, code)
+}
+
+// unmarkCodeUsed unmarks a code whose exchange failed. It does not use the
+// context of the exchange, which may be the cause of the failure, and ignores
+// errors since the exchange error is the one reported.
+func (c *Client) unmarkCodeUsed(code string) {
+	if c.UsedCodes == nil {
+		return
+	}
+	c.UsedCodes.Unmark(context.Background(), code)
+}
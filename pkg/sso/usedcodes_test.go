@@ -0,0 +1,155 @@
+package sso
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryUsedCodeStore struct {
+	mu    sync.Mutex
+	codes map[string]bool
+}
+
+func (s *memoryUsedCodeStore) Mark(ctx context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.codes[code] {
+		return ErrCodeAlreadyUsed
+	}
+	if s.codes == nil {
+		s.codes = make(map[string]bool)
+	}
+	s.codes[code] = true
+	return nil
+}
+
+func (s *memoryUsedCodeStore) Unmark(ctx context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.codes, code)
+	return nil
+}
+
+func TestClientUsedCodes(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		profileAndTokenTestHandler(w, r)
+	}))
+	defer server.Close()
+
+	store := &memoryUsedCodeStore{}
+	newClient := func() *Client {
+		return &Client{
+			HTTPClient: server.Client(),
+			Endpoint:   server.URL,
+			APIKey:     "test",
+			ClientID:   "client_123",
+			UsedCodes:  store,
+		}
+	}
+
+	_, err := newClient().GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+	require.NoError(t, err)
+
+	// The store is shared, so a code replayed against another instance is
+	// rejected as well.
+	_, err = newClient().GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+	require.Equal(t, ErrCodeAlreadyUsed, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	_, err = newClient().GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "other_code"})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestClientUsedCodesWithProfileCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(profileAndTokenTestHandler))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient:      server.Client(),
+		Endpoint:        server.URL,
+		APIKey:          "test",
+		ClientID:        "client_123",
+		ProfileCacheTTL: time.Minute,
+		UsedCodes:       &memoryUsedCodeStore{},
+	}
+
+	_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+	require.NoError(t, err)
+
+	_, err = client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+	require.Equal(t, ErrCodeAlreadyUsed, err)
+}
+
+func TestClientUsedCodesConcurrentExchanges(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		profileAndTokenTestHandler(w, r)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+		ClientID:   "client_123",
+		UsedCodes:  &memoryUsedCodeStore{},
+	}
+
+	var wg sync.WaitGroup
+	var succeeded int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+			if err == nil {
+				atomic.AddInt32(&succeeded, 1)
+				return
+			}
+			require.Equal(t, ErrCodeAlreadyUsed, err)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), atomic.LoadInt32(&succeeded))
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClientUsedCodesFailedExchange(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		profileAndTokenTestHandler(w, r)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+		ClientID:   "client_123",
+		UsedCodes:  &memoryUsedCodeStore{},
+	}
+
+	_, err := client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+	require.Error(t, err)
+	require.NotEqual(t, ErrCodeAlreadyUsed, err)
+
+	_, err = client.GetProfileAndToken(context.Background(), GetProfileAndTokenOpts{Code: "authorization_code"})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
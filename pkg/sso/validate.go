@@ -0,0 +1,115 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ConnectionWarningCode identifies a configuration issue of a Connection.
+type ConnectionWarningCode string
+
+// Constants that enumerate the configuration issues reported by
+// ValidateConnection.
+const (
+	// The Connection is not active and cannot authenticate users.
+	ConnectionNotActive ConnectionWarningCode = "connection_not_active"
+
+	// The Connection has no provider type.
+	ConnectionMissingType ConnectionWarningCode = "missing_connection_type"
+
+	// The Connection does not belong to an Organization.
+	ConnectionMissingOrganization ConnectionWarningCode = "missing_organization"
+
+	// The Connection has no domain, so users cannot be routed to it by their
+	// email domain.
+	ConnectionMissingDomains ConnectionWarningCode = "missing_domains"
+)
+
+// ConnectionWarning describes a configuration issue of a Connection.
+type ConnectionWarning struct {
+	// The code identifying the issue.
+	Code ConnectionWarningCode
+
+	// A human readable description of the issue.
+	Message string
+}
+
+// ValidateConnectionOpts contains the options to validate a Connection.
+type ValidateConnectionOpts struct {
+	// Connection unique identifier.
+	Connection string
+}
+
+// ValidationResult is the outcome of validating a Connection.
+type ValidationResult struct {
+	// The validated Connection.
+	Connection Connection
+
+	// The configuration issues found, if any.
+	Warnings []ConnectionWarning
+}
+
+// Valid reports whether no configuration issue was found.
+func (r ValidationResult) Valid() bool {
+	return len(r.Warnings) == 0
+}
+
+// ValidateConnection gets a Connection and checks its configuration before
+// users sign in with it. WorkOS does not expose an endpoint to test a
+// Connection against its Identity Provider, so the checks only cover the
+// Connection details returned by the API.
+func (c *Client) ValidateConnection(
+	ctx context.Context,
+	opts ValidateConnectionOpts,
+) (ValidationResult, error) {
+	if opts.Connection == "" {
+		return ValidationResult{}, errors.New("incomplete arguments: missing Connection")
+	}
+
+	connection, err := c.GetConnection(ctx, GetConnectionOpts{
+		Connection: opts.Connection,
+	})
+	if err != nil {
+		return ValidationResult{}, err
+	}
+
+	return ValidationResult{
+		Connection: connection,
+		Warnings:   validateConnection(connection),
+	}, nil
+}
+
+func validateConnection(connection Connection) []ConnectionWarning {
+	var warnings []ConnectionWarning
+
+	if connection.State != Active {
+		warnings = append(warnings, ConnectionWarning{
+			Code:    ConnectionNotActive,
+			Message: fmt.Sprintf("connection is %s", connection.State),
+		})
+	}
+
+	if connection.ConnectionType == "" {
+		warnings = append(warnings, ConnectionWarning{
+			Code:    ConnectionMissingType,
+			Message: "connection has no connection type",
+		})
+	}
+
+	if connection.OrganizationID == "" {
+		warnings = append(warnings, ConnectionWarning{
+			Code:    ConnectionMissingOrganization,
+			Message: "connection does not belong to an organization",
+		})
+	}
+
+	if len(connection.Domains) == 0 {
+		warnings = append(warnings, ConnectionWarning{
+			Code:    ConnectionMissingDomains,
+			Message: "connection has no domains",
+		})
+	}
+
+	return warnings
+}
@@ -0,0 +1,71 @@
+package sso
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientValidateConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/connections/conn_valid":
+			w.Write([]byte(`{
+				"id": "conn_valid",
+				"state": "active",
+				"connection_type": "OktaSAML",
+				"organization_id": "org_123",
+				"domains": [{"id": "conn_domain_123", "domain": "foo-corp.com"}]
+			}`))
+		case "/connections/conn_draft":
+			w.Write([]byte(`{"id": "conn_draft", "state": "draft", "domains": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:     "test",
+		Endpoint:   server.URL,
+		HTTPClient: server.Client(),
+	}
+
+	t.Run("valid connection", func(t *testing.T) {
+		result, err := client.ValidateConnection(context.Background(), ValidateConnectionOpts{
+			Connection: "conn_valid",
+		})
+		require.NoError(t, err)
+		require.True(t, result.Valid())
+		require.Equal(t, "conn_valid", result.Connection.ID)
+	})
+
+	t.Run("misconfigured connection", func(t *testing.T) {
+		result, err := client.ValidateConnection(context.Background(), ValidateConnectionOpts{
+			Connection: "conn_draft",
+		})
+		require.NoError(t, err)
+		require.False(t, result.Valid())
+		require.Equal(t, []ConnectionWarning{
+			{Code: ConnectionNotActive, Message: "connection is draft"},
+			{Code: ConnectionMissingType, Message: "connection has no connection type"},
+			{Code: ConnectionMissingOrganization, Message: "connection does not belong to an organization"},
+			{Code: ConnectionMissingDomains, Message: "connection has no domains"},
+		}, result.Warnings)
+	})
+
+	t.Run("unknown connection", func(t *testing.T) {
+		_, err := client.ValidateConnection(context.Background(), ValidateConnectionOpts{
+			Connection: "conn_unknown",
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("missing connection", func(t *testing.T) {
+		_, err := client.ValidateConnection(context.Background(), ValidateConnectionOpts{})
+		require.EqualError(t, err, "incomplete arguments: missing Connection")
+	})
+}
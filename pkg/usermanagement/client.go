@@ -6,8 +6,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"log"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-querystring/query"
@@ -20,6 +24,18 @@ import (
 // ResponseLimit is the default number of records to limit a response to.
 const ResponseLimit = 10
 
+// DefaultMembershipConcurrency is the default number of memberships created
+// concurrently by CreateOrganizationMemberships.
+const DefaultMembershipConcurrency = 5
+
+// DefaultForEachUserConcurrency is the default number of Users processed
+// concurrently by ForEachUser.
+const DefaultForEachUserConcurrency = 5
+
+// RollbackTimeout bounds the deletion of the Users created by CreateUsers
+// before a failure.
+const RollbackTimeout = 30 * time.Second
+
 // ScreenHint represents the screen to redirect the user to in Authkit
 type ScreenHint string
 
@@ -29,6 +45,23 @@ const (
 	SignIn ScreenHint = "sign-in"
 )
 
+// AuthenticationMethod represents how a User authenticated.
+type AuthenticationMethod string
+
+// Constants that enumerate the known authentication methods. Methods added to
+// the API later are decoded as is.
+const (
+	SSO            AuthenticationMethod = "SSO"
+	Password       AuthenticationMethod = "Password"
+	Passkey        AuthenticationMethod = "Passkey"
+	AppleOAuth     AuthenticationMethod = "AppleOAuth"
+	GitHubOAuth    AuthenticationMethod = "GitHubOAuth"
+	GoogleOAuth    AuthenticationMethod = "GoogleOAuth"
+	MicrosoftOAuth AuthenticationMethod = "MicrosoftOAuth"
+	MagicAuth      AuthenticationMethod = "MagicAuth"
+	Impersonation  AuthenticationMethod = "Impersonation"
+)
+
 // Order represents the order of records.
 type Order string
 
@@ -50,16 +83,18 @@ const (
 )
 
 type Invitation struct {
-	ID             string          `json:"id"`
-	Email          string          `json:"email"`
-	State          InvitationState `json:"state"`
-	AcceptedAt     string          `json:"accepted_at,omitempty"`
-	RevokedAt      string          `json:"revoked_at,omitempty"`
-	Token          string          `json:"token"`
-	OrganizationID string          `json:"organization_id,omitempty"`
-	ExpiresAt      string          `json:"expires_at"`
-	CreatedAt      string          `json:"created_at"`
-	UpdatedAt      string          `json:"updated_at"`
+	ID                  string          `json:"id"`
+	Email               string          `json:"email"`
+	State               InvitationState `json:"state"`
+	AcceptedAt          string          `json:"accepted_at,omitempty"`
+	RevokedAt           string          `json:"revoked_at,omitempty"`
+	Token               string          `json:"token"`
+	AcceptInvitationURL string          `json:"accept_invitation_url"`
+	OrganizationID      string          `json:"organization_id,omitempty"`
+	InviterUserID       string          `json:"inviter_user_id,omitempty"`
+	ExpiresAt           string          `json:"expires_at"`
+	CreatedAt           string          `json:"created_at"`
+	UpdatedAt           string          `json:"updated_at"`
 }
 
 // Organization contains data about a particular Organization.
@@ -76,8 +111,9 @@ type OrganizationMembershipStatus string
 
 // Constants that enumerate the status of an Organization Membership.
 const (
-	Active                        OrganizationMembershipStatus = "active"
-	PendingOrganizationMembership OrganizationMembershipStatus = "pending"
+	Active                         OrganizationMembershipStatus = "active"
+	PendingOrganizationMembership  OrganizationMembershipStatus = "pending"
+	InactiveOrganizationMembership OrganizationMembershipStatus = "inactive"
 )
 
 type RoleResponse struct {
@@ -124,17 +160,73 @@ type User struct {
 	// The User's email.
 	Email string `json:"email"`
 
+	// The identifier of the User in an external system, if any.
+	ExternalID string `json:"external_id"`
+
 	// The timestamp of when the User was created.
-	CreatedAt string `json:"created_at"`
+	CreatedAt time.Time `json:"created_at"`
 
 	// The timestamp of when the User was updated.
-	UpdatedAt string `json:"updated_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 
 	// Whether the User email is verified.
 	EmailVerified bool `json:"email_verified"`
 
 	// A URL reference to an image representing the User.
 	ProfilePictureURL string `json:"profile_picture_url"`
+
+	// The timestamp of the last sign in of the User. It is the zero time when
+	// the User never signed in.
+	LastSignInAt time.Time `json:"last_sign_in_at"`
+
+	// Custom key-value pairs attached to the User.
+	Metadata map[string]string `json:"metadata"`
+}
+
+// UnmarshalJSON decodes the User, treating null and empty timestamps as the
+// zero time.
+func (u *User) UnmarshalJSON(data []byte) error {
+	type user User
+	var v struct {
+		user
+		CreatedAt    workos.NullableTime `json:"created_at"`
+		UpdatedAt    workos.NullableTime `json:"updated_at"`
+		LastSignInAt workos.NullableTime `json:"last_sign_in_at"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*u = User(v.user)
+	u.CreatedAt = v.CreatedAt.Time
+	u.UpdatedAt = v.UpdatedAt.Time
+	u.LastSignInAt = v.LastSignInAt.Time
+	return nil
+}
+
+// FullName returns the first and last names of the User, falling back to the
+// local part of its email when both are empty.
+func (u User) FullName() string {
+	return workos.FullName(u.FirstName, u.LastName, u.Email)
+}
+
+// EmailDomain returns the lowercased domain of the email of the User, or an
+// empty string when the email is malformed.
+func (u User) EmailDomain() string {
+	return workos.EmailDomain(u.Email)
+}
+
+// FilterInactive returns the Users that didn't sign in since the given time,
+// including the Users that never signed in. The API doesn't support filtering
+// Users by last sign in, so it is done on the listed Users.
+func FilterInactive(users []User, before time.Time) []User {
+	var inactive []User
+	for _, u := range users {
+		if u.LastSignInAt.Before(before) {
+			inactive = append(inactive, u)
+		}
+	}
+	return inactive
 }
 
 // GetUserOpts contains the options to pass in order to get a user profile.
@@ -152,8 +244,11 @@ type ListUsersResponse struct {
 	ListMetadata common.ListMetadata `json:"list_metadata"`
 }
 
+// ListUsersOpts contains the options to list Users. User Management Users have
+// no managed or unmanaged type: any User may sign in with SSO, so Users
+// cannot be filtered by type.
 type ListUsersOpts struct {
-	// Filter Users by their email.
+	// Filter Users by their email. This is an exact, case-insensitive match.
 	Email string `url:"email,omitempty"`
 
 	// Filter Users by the organization they are members of.
@@ -162,7 +257,8 @@ type ListUsersOpts struct {
 	// Maximum number of records to return.
 	Limit int `url:"limit"`
 
-	// The order in which to paginate records.
+	// The order in which to paginate records, sorted by creation time.
+	// Defaults to Desc. Use Asc for stable incremental syncs.
 	Order Order `url:"order,omitempty"`
 
 	// Pagination cursor to receive records before a provided User ID.
@@ -172,12 +268,96 @@ type ListUsersOpts struct {
 	After string `url:"after,omitempty"`
 }
 
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListUsersOpts) NextPage(m common.ListMetadata) ListUsersOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListUsersOpts) PrevPage(m common.ListMetadata) ListUsersOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
+// ForEachUserOpts contains the options to process Users with ForEachUser.
+type ForEachUserOpts struct {
+	// The criteria of the Users to process. Pagination is handled by
+	// ForEachUser, starting at After when set.
+	ListUsersOpts
+
+	// The maximum number of Users processed concurrently.
+	// Defaults to DefaultForEachUserConcurrency.
+	Concurrency int
+
+	// Whether to keep processing Users when the callback returns an error.
+	// When false, ForEachUser stops at the first error and returns it. When
+	// true, every User is processed and the errors are returned as UserErrors.
+	ContinueOnError bool
+}
+
+// UserErrors holds the errors returned by the ForEachUser callback when
+// ContinueOnError is set.
+type UserErrors []error
+
+func (e UserErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d users failed, first error: %s", len(e), e[0])
+}
+
 type CreateUserOpts struct {
 	Email         string `json:"email"`
 	Password      string `json:"password,omitempty"`
 	FirstName     string `json:"first_name,omitempty"`
 	LastName      string `json:"last_name,omitempty"`
 	EmailVerified bool   `json:"email_verified,omitempty"`
+
+	// Custom key-value pairs to attach to the User.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// CreateUsersOpts contains the options to create several Users with
+// CreateUsers.
+type CreateUsersOpts struct {
+	// The Users to create, in order.
+	Users []CreateUserOpts
+
+	// Whether to delete the Users already created when a creation fails.
+	RollbackOnError bool
+}
+
+// CreateUsersError is returned by CreateUsers when a User could not be
+// created.
+type CreateUsersError struct {
+	// The index in CreateUsersOpts.Users of the User that could not be
+	// created.
+	Index int
+
+	// The error returned when creating the User.
+	Err error
+
+	// The errors returned when deleting the Users created before the failure.
+	// The Users they refer to were not rolled back.
+	RollbackErrors []error
+}
+
+func (e CreateUsersError) Error() string {
+	msg := fmt.Sprintf("user %d: %s", e.Index, e.Err)
+	if len(e.RollbackErrors) != 0 {
+		msg += fmt.Sprintf(" (%d users could not be rolled back, first error: %s)", len(e.RollbackErrors), e.RollbackErrors[0])
+	}
+	return msg
+}
+
+func (e CreateUsersError) Unwrap() error {
+	return e.Err
 }
 
 // The algorithm originally used to hash the password.
@@ -196,6 +376,24 @@ type UpdateUserOpts struct {
 	Password         string           `json:"password,omitempty"`
 	PasswordHash     string           `json:"password_hash,omitempty"`
 	PasswordHashType PasswordHashType `json:"password_hash_type,omitempty"`
+
+	// Custom key-value pairs to attach to the User.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// UpdateUserPasswordOpts contains the options to set the password of a User.
+type UpdateUserPasswordOpts struct {
+	// The User unique identifier.
+	User string
+
+	// The new password. Either Password or PasswordHash is REQUIRED.
+	Password string
+
+	// The hash of the new password.
+	PasswordHash string
+
+	// The algorithm used to compute PasswordHash.
+	PasswordHashType PasswordHashType
 }
 
 type DeleteUserOpts struct {
@@ -208,6 +406,11 @@ type AuthenticateWithPasswordOpts struct {
 	Password  string `json:"password"`
 	IPAddress string `json:"ip_address,omitempty"`
 	UserAgent string `json:"user_agent,omitempty"`
+
+	// The token of an Invitation accepted by the user. On success, the user
+	// joins the Organization of the Invitation, whose ID is returned in
+	// AuthenticateResponse.OrganizationID.
+	InvitationToken string `json:"invitation_token,omitempty"`
 }
 
 type AuthenticateWithCodeOpts struct {
@@ -215,6 +418,11 @@ type AuthenticateWithCodeOpts struct {
 	Code      string `json:"code"`
 	IPAddress string `json:"ip_address,omitempty"`
 	UserAgent string `json:"user_agent,omitempty"`
+
+	// The token of an Invitation accepted by the user. On success, the user
+	// joins the Organization of the Invitation, whose ID is returned in
+	// AuthenticateResponse.OrganizationID.
+	InvitationToken string `json:"invitation_token,omitempty"`
 }
 
 type AuthenticateWithRefreshTokenOpts struct {
@@ -235,6 +443,11 @@ type AuthenticateWithMagicAuthOpts struct {
 	LinkAuthorizationCode string `json:"link_authorization_code,omitempty"`
 	IPAddress             string `json:"ip_address,omitempty"`
 	UserAgent             string `json:"user_agent,omitempty"`
+
+	// The token of an Invitation accepted by the user. On success, the user
+	// joins the Organization of the Invitation, whose ID is returned in
+	// AuthenticateResponse.OrganizationID.
+	InvitationToken string `json:"invitation_token,omitempty"`
 }
 
 type AuthenticateWithTOTPOpts struct {
@@ -270,14 +483,17 @@ type Impersonator struct {
 	Reason string `json:"reason"`
 }
 
+// AuthenticateResponse is returned by the Authenticate* methods. Optional
+// objects, such as Impersonator, are nil when absent from the response.
 type AuthenticateResponse struct {
+	// The authenticated User.
 	User User `json:"user"`
 
 	// Which Organization the user is signing in to.
 	// If the user is a member of multiple organizations, this is the organization the user selected
 	// as part of the authentication flow.
 	// If the user is a member of only one organization, this is that organization.
-	// If the user is not a member of any organizations, this is null.
+	// If the user is not a member of any organizations, this is empty.
 	OrganizationID string `json:"organization_id"`
 
 	// The AccessToken can be validated to confirm that a user has an active session.
@@ -289,6 +505,9 @@ type AuthenticateResponse struct {
 
 	// Present if the authenticated user is being impersonated.
 	Impersonator *Impersonator `json:"impersonator"`
+
+	// How the user authenticated.
+	AuthenticationMethod AuthenticationMethod `json:"authentication_method"`
 }
 
 type RefreshAuthenticationResponse struct {
@@ -303,6 +522,10 @@ type RefreshAuthenticationResponse struct {
 type SendVerificationEmailOpts struct {
 	// The unique ID of the User who will be sent a verification email.
 	User string
+
+	// The locale of the email, such as "fr" or "pt-BR". Defaults to the
+	// locale configured in the WorkOS Dashboard.
+	Locale string
 }
 
 type VerifyEmailOpts struct {
@@ -318,6 +541,10 @@ type SendPasswordResetEmailOpts struct {
 
 	// The URL that will be linked to in the verification email.
 	PasswordResetUrl string `json:"password_reset_url"`
+
+	// The locale of the email, such as "fr" or "pt-BR". Defaults to the
+	// locale configured in the WorkOS Dashboard.
+	Locale string `json:"locale,omitempty"`
 }
 
 type ResetPasswordOpts struct {
@@ -335,6 +562,10 @@ type UserResponse struct {
 type SendMagicAuthCodeOpts struct {
 	// The email address the one-time code will be sent to.
 	Email string `json:"email"`
+
+	// The locale of the email, such as "fr" or "pt-BR". Defaults to the
+	// locale configured in the WorkOS Dashboard.
+	Locale string `json:"locale,omitempty"`
 }
 
 type EnrollAuthFactorOpts struct {
@@ -386,6 +617,23 @@ type ListOrganizationMembershipsOpts struct {
 	After string `url:"after,omitempty"`
 }
 
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListOrganizationMembershipsOpts) NextPage(m common.ListMetadata) ListOrganizationMembershipsOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListOrganizationMembershipsOpts) PrevPage(m common.ListMetadata) ListOrganizationMembershipsOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
 type ListOrganizationMembershipsResponse struct {
 	Data []OrganizationMembership `json:"data"`
 
@@ -404,17 +652,86 @@ type CreateOrganizationMembershipOpts struct {
 	RoleSlug string `json:"role_slug,omitempty"`
 }
 
+// MembershipRequest describes a User to add to an Organization.
+type MembershipRequest struct {
+	// The ID of the User to add as a member.
+	UserID string
+
+	// The slug of the Role in which to grant this membership. If no RoleSlug is given, the default role will be granted.
+	// OPTIONAL
+	RoleSlug string
+}
+
+// CreateOrganizationMembershipsOpts contains the options to add several Users
+// to an Organization.
+type CreateOrganizationMembershipsOpts struct {
+	// The ID of the Organization in which to add the Users as members.
+	OrganizationID string
+
+	// The Users to add as members.
+	Members []MembershipRequest
+
+	// The maximum number of memberships created concurrently.
+	// Defaults to DefaultMembershipConcurrency.
+	Concurrency int
+}
+
+// CreateOrganizationMembershipResult is the outcome of adding one User to an
+// Organization.
+type CreateOrganizationMembershipResult struct {
+	// The ID of the User added as a member.
+	UserID string
+
+	// The created OrganizationMembership, when Err is nil.
+	OrganizationMembership OrganizationMembership
+
+	// The error that prevented the membership creation, if any.
+	Err error
+}
+
 type UpdateOrganizationMembershipOpts struct {
 	// The slug of the Role to update to for this membership.
 	// OPTIONAL
 	RoleSlug string `json:"role_slug,omitempty"`
 }
 
+// ActivateOrganizationMembershipOpts contains the options to activate an
+// Organization Membership.
+type ActivateOrganizationMembershipOpts struct {
+	// The ID of the Organization Membership to activate.
+	OrganizationMembership string
+}
+
+// DeactivateOrganizationMembershipOpts contains the options to deactivate an
+// Organization Membership.
+type DeactivateOrganizationMembershipOpts struct {
+	// The ID of the Organization Membership to deactivate.
+	OrganizationMembership string
+}
+
 type DeleteOrganizationMembershipOpts struct {
 	// The ID of the Organization Membership to delete.
 	OrganizationMembership string
 }
 
+// RemoveUserFromAllOrganizationsOpts contains the options to remove a User
+// from every Organization it is a member of.
+type RemoveUserFromAllOrganizationsOpts struct {
+	// The ID of the User to remove.
+	User string
+}
+
+// MembershipErrors holds the errors of the Organization Memberships that
+// RemoveUserFromAllOrganizations failed to delete.
+type MembershipErrors []error
+
+func (e MembershipErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d memberships could not be removed, first error: %s", len(e), e[0])
+}
+
 type GetInvitationOpts struct {
 	Invitation string
 }
@@ -429,9 +746,11 @@ type ListInvitationsResponse struct {
 }
 
 type ListInvitationsOpts struct {
-	OrganizationID string `json:"organization_id,omitempty"`
+	// Filter Invitations by the Organization they were sent for.
+	OrganizationID string `url:"organization_id,omitempty"`
 
-	Email string `json:"email,omitempty"`
+	// Filter Invitations by the invited email.
+	Email string `url:"email,omitempty"`
 
 	// Maximum number of records to return.
 	Limit int `url:"limit"`
@@ -446,11 +765,32 @@ type ListInvitationsOpts struct {
 	After string `url:"after,omitempty"`
 }
 
+// NextPage returns the options requesting the page after the one described by
+// m, keeping the other options. There is no next page when m.After is empty.
+func (opts ListInvitationsOpts) NextPage(m common.ListMetadata) ListInvitationsOpts {
+	opts.Before = ""
+	opts.After = m.After
+	return opts
+}
+
+// PrevPage returns the options requesting the page before the one described
+// by m, keeping the other options. There is no previous page when m.Before is
+// empty.
+func (opts ListInvitationsOpts) PrevPage(m common.ListMetadata) ListInvitationsOpts {
+	opts.Before = m.Before
+	opts.After = ""
+	return opts
+}
+
 type SendInvitationOpts struct {
 	Email          string `json:"email"`
 	OrganizationID string `json:"organization_id,omitempty"`
 	ExpiresInDays  int    `json:"expires_in_days,omitempty"`
 	InviterUserID  string `json:"inviter_user_id,omitempty"`
+
+	// The slug of the Role granted to the invited User once they join the
+	// Organization. Defaults to the default Role of the Organization.
+	RoleSlug string `json:"role_slug,omitempty"`
 }
 
 type RevokeInvitationOpts struct {
@@ -461,17 +801,108 @@ type RevokeSessionOpts struct {
 	SessionID string `json:"session_id"`
 }
 
-func NewClient(apiKey string) *Client {
-	return &Client{
+// ClientOption configures a Client created with NewClient.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the http.Client used to send requests to WorkOS.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithEndpoint sets the endpoint to WorkOS API.
+func WithEndpoint(endpoint string) ClientOption {
+	return func(c *Client) {
+		c.Endpoint = endpoint
+	}
+}
+
+// WithTimeout sets the timeout of the requests sent to WorkOS. It is applied
+// after the other options, whatever their order. The http.Client is copied so
+// that a client given to WithHTTPClient is left untouched.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger used to log every request sent to WorkOS.
+func WithLogger(logger *log.Logger) ClientOption {
+	return func(c *Client) {
+		c.Logger = logger
+	}
+}
+
+// WithObserver sets the observer notified after every request sent to WorkOS.
+func WithObserver(observer common.Observer) ClientOption {
+	return func(c *Client) {
+		c.Observer = observer
+	}
+}
+
+// WithRequestEditor adds a function applied to every request before it is
+// sent to WorkOS.
+func WithRequestEditor(editor func(*http.Request) error) ClientOption {
+	return func(c *Client) {
+		c.RequestEditors = append(c.RequestEditors, editor)
+	}
+}
+
+// NewClient returns a Client configured with the given options.
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	c := &Client{
 		APIKey:     apiKey,
 		Endpoint:   "https://api.workos.com",
-		HTTPClient: &http.Client{Timeout: time.Second * 10},
+		HTTPClient: workos.NewHTTPClient(time.Second * 10),
 		JSONEncode: json.Marshal,
 	}
+	c.ownedTransport = c.HTTPClient.Transport
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.timeout > 0 {
+		owned := c.HTTPClient == nil
+		c.HTTPClient = workos.WithTimeout(c.HTTPClient, c.timeout)
+		if owned {
+			c.ownedTransport = c.HTTPClient.Transport
+		}
+	}
+
+	return c
+}
+
+// observer returns the observer notified after every request, logging them
+// when Logger is set.
+func (c *Client) observer() common.Observer {
+	return workos.LogObserver(c.Logger, c.Observer)
+}
+
+func (c *Client) init() {
+	c.Endpoint = workos.NormalizeEndpoint(c.Endpoint, workos.DefaultEndpoint)
+
+	if c.HTTPClient == nil {
+		c.HTTPClient = workos.NewHTTPClient(time.Second * 10)
+		c.ownedTransport = c.HTTPClient.Transport
+	}
+
+	if c.JSONEncode == nil {
+		c.JSONEncode = json.Marshal
+	}
+}
+
+// Close closes the idle connections of the transport created by the Client
+// when HTTPClient is not set. It is a no-op for a user-supplied HTTPClient.
+func (c *Client) Close() {
+	workos.CloseIdleConnections(c.HTTPClient, c.ownedTransport)
 }
 
 // GetUser returns details of an existing user
 func (c *Client) GetUser(ctx context.Context, opts GetUserOpts) (User, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s",
 		c.Endpoint,
@@ -487,11 +918,12 @@ func (c *Client) GetUser(ctx context.Context, opts GetUserOpts) (User, error) {
 		return User{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return User{}, err
 	}
@@ -505,11 +937,22 @@ func (c *Client) GetUser(ctx context.Context, opts GetUserOpts) (User, error) {
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body)
+
 	return body, err
 }
 
 // ListUsers get a list of all of your existing users matching the criteria specified.
 func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts) (ListUsersResponse, error) {
+	c.once.Do(c.init)
+
+	if err := common.ValidateCursor("before", opts.Before); err != nil {
+		return ListUsersResponse{}, err
+	}
+	if err := common.ValidateCursor("after", opts.After); err != nil {
+		return ListUsersResponse{}, err
+	}
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users",
 		c.Endpoint,
@@ -524,13 +967,12 @@ func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts) (ListUsersRe
 		return ListUsersResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	if opts.Order == "" {
 		opts.Order = Desc
@@ -543,7 +985,7 @@ func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts) (ListUsersRe
 
 	req.URL.RawQuery = queryValues.Encode()
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return ListUsersResponse{}, err
 	}
@@ -557,12 +999,288 @@ func (c *Client) ListUsers(ctx context.Context, opts ListUsersOpts) (ListUsersRe
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	for i := range body.Data {
+		c.normalizeUser(&body.Data[i])
+	}
+
 	return body, err
 }
 
+// ForEachUser lists the Users matching the given criteria, page by page, and
+// calls fn for each of them with at most opts.Concurrency calls running at the
+// same time. The context given to fn is cancelled when ForEachUser stops at an
+// error.
+func (c *Client) ForEachUser(ctx context.Context, opts ForEachUserOpts, fn func(context.Context, User) error) error {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultForEachUserConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu   sync.Mutex
+		errs UserErrors
+		wg   sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(errs) != 0 && !opts.ContinueOnError
+	}
+
+	listOpts := opts.ListUsersOpts
+	var listErr error
+
+pages:
+	for {
+		res, err := c.ListUsers(ctx, listOpts)
+		if err != nil {
+			listErr = err
+			break
+		}
+
+		for _, user := range res.Data {
+			sem <- struct{}{}
+			if failed() {
+				<-sem
+				break pages
+			}
+			wg.Add(1)
+
+			go func(user User) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+
+				if err := fn(ctx, user); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+
+					if !opts.ContinueOnError {
+						cancel()
+					}
+				}
+			}(user)
+		}
+
+		if res.ListMetadata.After == "" {
+			break
+		}
+		listOpts.After = res.ListMetadata.After
+	}
+
+	wg.Wait()
+
+	switch {
+	case len(errs) != 0 && !opts.ContinueOnError:
+		return errs[0]
+	case listErr != nil:
+		return listErr
+	case len(errs) != 0:
+		return errs
+	}
+	return nil
+}
+
+// StreamUsers lists the Users matching the given criteria, page by page, and
+// sends them on the returned channel. The next page is only requested once
+// every User of the current page has been received. Both channels are closed
+// once the Users are exhausted, the context is cancelled or a request fails.
+// The error channel delivers at most one error.
+func (c *Client) StreamUsers(ctx context.Context, opts ListUsersOpts) (<-chan User, <-chan error) {
+	users := make(chan User)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(users)
+
+		for {
+			res, err := c.ListUsers(ctx, opts)
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			for _, user := range res.Data {
+				select {
+				case users <- user:
+				case <-ctx.Done():
+					errs <- ctx.Err()
+					return
+				}
+			}
+
+			if res.ListMetadata.After == "" {
+				return
+			}
+			opts.After = res.ListMetadata.After
+		}
+	}()
+
+	return users, errs
+}
+
+// normalizeUser lowercases the email of the User when NormalizeEmail is set.
+func (c *Client) normalizeUser(u *User) {
+	if c.NormalizeEmail {
+		u.Email = strings.ToLower(u.Email)
+	}
+}
+
+// Ping checks that WorkOS is reachable and that the API key is valid, with a
+// lightweight authenticated request that has no side effects. An invalid API
+// key results in an error for which workos_errors.IsUnauthorized is true.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.ListUsers(ctx, ListUsersOpts{Limit: 1})
+	return err
+}
+
+// GetUserByEmailOpts contains the options to look up a User by email.
+type GetUserByEmailOpts struct {
+	// The email of the User. This is an exact, case-insensitive match.
+	Email string
+}
+
+// GetUserByEmail looks up the User with the given email. It returns false with
+// a nil error when no User matches.
+func (c *Client) GetUserByEmail(ctx context.Context, opts GetUserByEmailOpts) (User, bool, error) {
+	if opts.Email == "" {
+		return User{}, false, errors.New("incomplete arguments: missing Email")
+	}
+
+	res, err := c.ListUsers(ctx, ListUsersOpts{
+		Email: opts.Email,
+		Limit: 1,
+	})
+	if err != nil {
+		return User{}, false, err
+	}
+
+	if len(res.Data) == 0 {
+		return User{}, false, nil
+	}
+
+	return res.Data[0], true, nil
+}
+
+// GetUserByExternalIDOpts contains the options to look up a User by external
+// identifier.
+type GetUserByExternalIDOpts struct {
+	// The identifier of the User in an external system.
+	ExternalID string
+}
+
+// GetUserByExternalID looks up the User with the given external identifier. It
+// returns false with a nil error when no User matches.
+func (c *Client) GetUserByExternalID(ctx context.Context, opts GetUserByExternalIDOpts) (User, bool, error) {
+	if opts.ExternalID == "" {
+		return User{}, false, errors.New("incomplete arguments: missing ExternalID")
+	}
+
+	c.once.Do(c.init)
+
+	endpoint := fmt.Sprintf(
+		"%s/user_management/users/external_id/%s",
+		c.Endpoint,
+		url.PathEscape(opts.ExternalID),
+	)
+
+	req, err := http.NewRequest(
+		http.MethodGet,
+		endpoint,
+		nil,
+	)
+	if err != nil {
+		return User{}, false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
+	if err != nil {
+		return User{}, false, err
+	}
+	defer res.Body.Close()
+
+	if err = workos_errors.TryGetHTTPError(res); err != nil {
+		if errors.Is(err, workos_errors.ErrNotFound) {
+			return User{}, false, nil
+		}
+		return User{}, false, err
+	}
+
+	var body User
+	dec := json.NewDecoder(res.Body)
+	if err = dec.Decode(&body); err != nil {
+		return User{}, false, err
+	}
+
+	c.normalizeUser(&body)
+
+	return body, true, nil
+}
+
+// GetUserBySSOProfileOpts contains the options to look up the User matching
+// an SSO Profile.
+type GetUserBySSOProfileOpts struct {
+	// The email of the SSO Profile.
+	Email string
+
+	// The Organization of the SSO Profile.
+	OrganizationID string
+}
+
+// GetUserBySSOProfile looks up the User whose email matches the email of an
+// SSO Profile and who is a member of the Organization of that Profile. It
+// returns false with a nil error when no User matches, in which case the User
+// can be created from the Profile.
+//
+// Users are never matched across Organizations: an SSO connection is only
+// trusted for the emails of its own Organization, so linking on the email
+// alone would let any Organization take over the Users of another.
+func (c *Client) GetUserBySSOProfile(ctx context.Context, opts GetUserBySSOProfileOpts) (User, bool, error) {
+	if opts.Email == "" {
+		return User{}, false, errors.New("incomplete arguments: missing Email")
+	}
+	if opts.OrganizationID == "" {
+		return User{}, false, errors.New("incomplete arguments: missing OrganizationID")
+	}
+
+	res, err := c.ListUsers(ctx, ListUsersOpts{
+		Email:          opts.Email,
+		OrganizationID: opts.OrganizationID,
+		Limit:          1,
+	})
+	if err != nil {
+		return User{}, false, err
+	}
+
+	if len(res.Data) == 0 || !strings.EqualFold(res.Data[0].Email, opts.Email) {
+		return User{}, false, nil
+	}
+
+	return res.Data[0], true, nil
+}
+
 // CreateUser create a new user with email password authentication.
 // Only unmanaged users can be created directly using the User Management API.
 func (c *Client) CreateUser(ctx context.Context, opts CreateUserOpts) (User, error) {
+	c.once.Do(c.init)
+
+	if err := workos.ValidateMetadata(opts.Metadata); err != nil {
+		return User{}, err
+	}
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users",
 		c.Endpoint,
@@ -582,11 +1300,12 @@ func (c *Client) CreateUser(ctx context.Context, opts CreateUserOpts) (User, err
 		return User{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return User{}, err
 	}
@@ -600,11 +1319,62 @@ func (c *Client) CreateUser(ctx context.Context, opts CreateUserOpts) (User, err
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body)
+
 	return body, err
 }
 
+// CreateUsers creates the given Users sequentially, stopping at the first
+// failure with a CreateUsersError. It returns the created Users.
+//
+// WorkOS has no transactional batch creation: with opts.RollbackOnError, the
+// Users created before the failure are deleted on a best-effort basis, within
+// RollbackTimeout and even when ctx is done. Those
+// that could not be deleted are still returned and their errors are reported
+// in CreateUsersError.RollbackErrors.
+func (c *Client) CreateUsers(ctx context.Context, opts CreateUsersOpts) ([]User, error) {
+	users := make([]User, 0, len(opts.Users))
+	for i, userOpts := range opts.Users {
+		user, err := c.CreateUser(ctx, userOpts)
+		if err == nil {
+			users = append(users, user)
+			continue
+		}
+
+		createErr := CreateUsersError{Index: i, Err: err}
+		if !opts.RollbackOnError {
+			return users, createErr
+		}
+
+		// The rollback must not be aborted by the cancellation that may have
+		// caused the failure.
+		rollbackCtx, cancel := context.WithTimeout(
+			common.WithCorrelationID(context.Background(), common.CorrelationID(ctx)),
+			RollbackTimeout,
+		)
+		defer cancel()
+
+		var remaining []User
+		for _, created := range users {
+			if err := c.DeleteUser(rollbackCtx, DeleteUserOpts{User: created.ID}); err != nil {
+				remaining = append(remaining, created)
+				createErr.RollbackErrors = append(createErr.RollbackErrors, fmt.Errorf("user %s: %w", created.ID, err))
+			}
+		}
+		return remaining, createErr
+	}
+
+	return users, nil
+}
+
 // UpdateUser updates User attributes.
 func (c *Client) UpdateUser(ctx context.Context, opts UpdateUserOpts) (User, error) {
+	c.once.Do(c.init)
+
+	if err := workos.ValidateMetadata(opts.Metadata); err != nil {
+		return User{}, err
+	}
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s",
 		c.Endpoint,
@@ -625,16 +1395,25 @@ func (c *Client) UpdateUser(ctx context.Context, opts UpdateUserOpts) (User, err
 		return User{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return User{}, err
 	}
 	defer res.Body.Close()
 
+	if err = tryGetPasswordStrengthError(res); err != nil {
+		return User{}, err
+	}
+
+	if err = tryGetPasswordNotAllowedError(res, opts.User); err != nil {
+		return User{}, err
+	}
+
 	if err = workos_errors.TryGetHTTPError(res); err != nil {
 		return User{}, err
 	}
@@ -643,11 +1422,15 @@ func (c *Client) UpdateUser(ctx context.Context, opts UpdateUserOpts) (User, err
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body)
+
 	return body, err
 }
 
 // DeleteUser delete an existing user.
 func (c *Client) DeleteUser(ctx context.Context, opts DeleteUserOpts) error {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s",
 		c.Endpoint,
@@ -663,11 +1446,12 @@ func (c *Client) DeleteUser(ctx context.Context, opts DeleteUserOpts) error {
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
@@ -722,6 +1506,7 @@ type GetAuthorizationURLOpts struct {
 // connection_id, organization_id, or provider.
 // These connection selectors are mutually exclusive, and exactly one must be provided.
 func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
+	c.once.Do(c.init)
 
 	query := make(url.Values, 5)
 	query.Set("client_id", opts.ClientID)
@@ -772,8 +1557,34 @@ func (c *Client) GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, er
 	return u, nil
 }
 
+// GetSignUpURL generates an AuthKit authorization URL opening the sign up
+// screen. Provider defaults to authkit and ScreenHint is set to SignUp.
+func (c *Client) GetSignUpURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
+	return c.getAuthKitURL(opts, SignUp)
+}
+
+// GetSignInURL generates an AuthKit authorization URL opening the sign in
+// screen. Provider defaults to authkit and ScreenHint is set to SignIn.
+func (c *Client) GetSignInURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
+	return c.getAuthKitURL(opts, SignIn)
+}
+
+func (c *Client) getAuthKitURL(opts GetAuthorizationURLOpts, hint ScreenHint) (*url.URL, error) {
+	if opts.Provider == "" {
+		opts.Provider = "authkit"
+	}
+	opts.ScreenHint = hint
+	return c.GetAuthorizationURL(opts)
+}
+
 // AuthenticateWithPassword authenticates a user with Email and Password
 func (c *Client) AuthenticateWithPassword(ctx context.Context, opts AuthenticateWithPasswordOpts) (AuthenticateResponse, error) {
+	c.once.Do(c.init)
+
+	if c.APIKey == "" {
+		return AuthenticateResponse{}, workos_errors.ErrMissingAPIKey
+	}
+
 	payload := struct {
 		AuthenticateWithPasswordOpts
 		ClientSecret string `json:"client_secret"`
@@ -801,11 +1612,12 @@ func (c *Client) AuthenticateWithPassword(ctx context.Context, opts Authenticate
 
 	// Add headers and context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -820,11 +1632,19 @@ func (c *Client) AuthenticateWithPassword(ctx context.Context, opts Authenticate
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
 // AuthenticateWithCode authenticates an OAuth user or a managed SSO user that is logging in through SSO
 func (c *Client) AuthenticateWithCode(ctx context.Context, opts AuthenticateWithCodeOpts) (AuthenticateResponse, error) {
+	c.once.Do(c.init)
+
+	if c.APIKey == "" {
+		return AuthenticateResponse{}, workos_errors.ErrMissingAPIKey
+	}
+
 	payload := struct {
 		AuthenticateWithCodeOpts
 		ClientSecret string `json:"client_secret"`
@@ -852,11 +1672,12 @@ func (c *Client) AuthenticateWithCode(ctx context.Context, opts AuthenticateWith
 
 	// Add headers and context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -871,12 +1692,20 @@ func (c *Client) AuthenticateWithCode(ctx context.Context, opts AuthenticateWith
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
 // AuthenticateWithRefreshToken obtains a new AccessToken and RefreshToken for
 // an existing session
 func (c *Client) AuthenticateWithRefreshToken(ctx context.Context, opts AuthenticateWithRefreshTokenOpts) (RefreshAuthenticationResponse, error) {
+	c.once.Do(c.init)
+
+	if c.APIKey == "" {
+		return RefreshAuthenticationResponse{}, workos_errors.ErrMissingAPIKey
+	}
+
 	payload := struct {
 		AuthenticateWithRefreshTokenOpts
 		ClientSecret string `json:"client_secret"`
@@ -904,11 +1733,12 @@ func (c *Client) AuthenticateWithRefreshToken(ctx context.Context, opts Authenti
 
 	// Add headers and context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return RefreshAuthenticationResponse{}, err
 	}
@@ -929,6 +1759,12 @@ func (c *Client) AuthenticateWithRefreshToken(ctx context.Context, opts Authenti
 // AuthenticateWithMagicAuth authenticates a user by verifying a one-time code sent to the user's email address by
 // the Magic Auth Send Code endpoint.
 func (c *Client) AuthenticateWithMagicAuth(ctx context.Context, opts AuthenticateWithMagicAuthOpts) (AuthenticateResponse, error) {
+	c.once.Do(c.init)
+
+	if c.APIKey == "" {
+		return AuthenticateResponse{}, workos_errors.ErrMissingAPIKey
+	}
+
 	payload := struct {
 		AuthenticateWithMagicAuthOpts
 		ClientSecret string `json:"client_secret"`
@@ -956,11 +1792,12 @@ func (c *Client) AuthenticateWithMagicAuth(ctx context.Context, opts Authenticat
 
 	// Add headers and context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -975,11 +1812,19 @@ func (c *Client) AuthenticateWithMagicAuth(ctx context.Context, opts Authenticat
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
 // AuthenticateWithTOTP authenticates a user by verifying a time-based one-time password (TOTP)
 func (c *Client) AuthenticateWithTOTP(ctx context.Context, opts AuthenticateWithTOTPOpts) (AuthenticateResponse, error) {
+	c.once.Do(c.init)
+
+	if c.APIKey == "" {
+		return AuthenticateResponse{}, workos_errors.ErrMissingAPIKey
+	}
+
 	payload := struct {
 		AuthenticateWithTOTPOpts
 		ClientSecret string `json:"client_secret"`
@@ -1007,11 +1852,12 @@ func (c *Client) AuthenticateWithTOTP(ctx context.Context, opts AuthenticateWith
 
 	// Add headers and context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -1026,11 +1872,19 @@ func (c *Client) AuthenticateWithTOTP(ctx context.Context, opts AuthenticateWith
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
 // AuthenticateWithEmailVerificationCode authenticates a user by verifying a code sent to their email address
 func (c *Client) AuthenticateWithEmailVerificationCode(ctx context.Context, opts AuthenticateWithEmailVerificationCodeOpts) (AuthenticateResponse, error) {
+	c.once.Do(c.init)
+
+	if c.APIKey == "" {
+		return AuthenticateResponse{}, workos_errors.ErrMissingAPIKey
+	}
+
 	payload := struct {
 		AuthenticateWithEmailVerificationCodeOpts
 		ClientSecret string `json:"client_secret"`
@@ -1058,11 +1912,12 @@ func (c *Client) AuthenticateWithEmailVerificationCode(ctx context.Context, opts
 
 	// Add headers and context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -1077,11 +1932,19 @@ func (c *Client) AuthenticateWithEmailVerificationCode(ctx context.Context, opts
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
 // AuthenticateWithOrganizationSelection completes authentication for a user given an organization they've selected.
 func (c *Client) AuthenticateWithOrganizationSelection(ctx context.Context, opts AuthenticateWithOrganizationSelectionOpts) (AuthenticateResponse, error) {
+	c.once.Do(c.init)
+
+	if c.APIKey == "" {
+		return AuthenticateResponse{}, workos_errors.ErrMissingAPIKey
+	}
+
 	payload := struct {
 		AuthenticateWithOrganizationSelectionOpts
 		ClientSecret string `json:"client_secret"`
@@ -1109,11 +1972,12 @@ func (c *Client) AuthenticateWithOrganizationSelection(ctx context.Context, opts
 
 	// Add headers and context to the request
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Content-Type", "application/json")
 
 	// Execute the request
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return AuthenticateResponse{}, err
 	}
@@ -1128,30 +1992,43 @@ func (c *Client) AuthenticateWithOrganizationSelection(ctx context.Context, opts
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
 // SendVerificationEmail creates an email verification challenge and emails verification token to user.
 func (c *Client) SendVerificationEmail(ctx context.Context, opts SendVerificationEmailOpts) (UserResponse, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/email_verification/send",
 		c.Endpoint,
 		opts.User,
 	)
+
+	data, err := c.JSONEncode(struct {
+		Locale string `json:"locale,omitempty"`
+	}{opts.Locale})
+	if err != nil {
+		return UserResponse{}, err
+	}
+
 	req, err := http.NewRequest(
 		http.MethodPost,
 		endpoint,
-		nil,
+		bytes.NewBuffer(data),
 	)
 	if err != nil {
 		return UserResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return UserResponse{}, err
 	}
@@ -1165,11 +2042,15 @@ func (c *Client) SendVerificationEmail(ctx context.Context, opts SendVerificatio
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
 // VerifyEmail verifies a user's email using the verification token that was sent to the user.
 func (c *Client) VerifyEmail(ctx context.Context, opts VerifyEmailOpts) (UserResponse, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/email_verification/confirm",
 		c.Endpoint,
@@ -1190,11 +2071,12 @@ func (c *Client) VerifyEmail(ctx context.Context, opts VerifyEmailOpts) (UserRes
 		return UserResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return UserResponse{}, err
 	}
@@ -1208,12 +2090,16 @@ func (c *Client) VerifyEmail(ctx context.Context, opts VerifyEmailOpts) (UserRes
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
 // SendPasswordResetEmail creates a password reset challenge and emails a password reset link to an
 // unmanaged user.
 func (c *Client) SendPasswordResetEmail(ctx context.Context, opts SendPasswordResetEmailOpts) error {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/password_reset/send",
 		c.Endpoint,
@@ -1233,11 +2119,12 @@ func (c *Client) SendPasswordResetEmail(ctx context.Context, opts SendPasswordRe
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
@@ -1248,6 +2135,8 @@ func (c *Client) SendPasswordResetEmail(ctx context.Context, opts SendPasswordRe
 
 // ResetPassword resets user password using token that was sent to the user.
 func (c *Client) ResetPassword(ctx context.Context, opts ResetPasswordOpts) (UserResponse, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/password_reset/confirm",
 		c.Endpoint,
@@ -1267,16 +2156,21 @@ func (c *Client) ResetPassword(ctx context.Context, opts ResetPasswordOpts) (Use
 		return UserResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return UserResponse{}, err
 	}
 	defer res.Body.Close()
 
+	if err = tryGetPasswordStrengthError(res); err != nil {
+		return UserResponse{}, err
+	}
+
 	if err = workos_errors.TryGetHTTPError(res); err != nil {
 		return UserResponse{}, err
 	}
@@ -1285,11 +2179,149 @@ func (c *Client) ResetPassword(ctx context.Context, opts ResetPasswordOpts) (Use
 	dec := json.NewDecoder(res.Body)
 	err = dec.Decode(&body)
 
+	c.normalizeUser(&body.User)
+
 	return body, err
 }
 
+// UpdateUserPassword sets the password of a User. It returns a
+// PasswordNotAllowedError when WorkOS rejects the password because the User
+// is passwordless.
+func (c *Client) UpdateUserPassword(ctx context.Context, opts UpdateUserPasswordOpts) (User, error) {
+	if opts.User == "" {
+		return User{}, errors.New("incomplete arguments: missing User")
+	}
+	if opts.Password == "" && opts.PasswordHash == "" {
+		return User{}, errors.New("incomplete arguments: missing Password or PasswordHash")
+	}
+
+	return c.UpdateUser(ctx, UpdateUserOpts{
+		User:             opts.User,
+		Password:         opts.Password,
+		PasswordHash:     opts.PasswordHash,
+		PasswordHashType: opts.PasswordHashType,
+	})
+}
+
+// passwordNotAllowedCode is the error code WorkOS returns when a password is
+// set on a passwordless User.
+const passwordNotAllowedCode = "password_not_allowed"
+
+// PasswordNotAllowedError is returned when setting the password of a
+// passwordless User.
+type PasswordNotAllowedError struct {
+	// The User unique identifier.
+	User string
+
+	// The error returned by WorkOS.
+	Err error
+}
+
+func (e PasswordNotAllowedError) Error() string {
+	return fmt.Sprintf("user %s is passwordless and cannot have a password set", e.User)
+}
+
+// Unwrap returns the error returned by WorkOS.
+func (e PasswordNotAllowedError) Unwrap() error {
+	return e.Err
+}
+
+// PasswordStrengthError is returned when a password is rejected by the
+// password policy.
+type PasswordStrengthError struct {
+	// The error message.
+	Message string
+
+	// Why the password was rejected (eg. too short, found in a breach).
+	Reasons []string
+
+	// Suggestions to pick a stronger password.
+	Suggestions []string
+}
+
+func (e PasswordStrengthError) Error() string {
+	if len(e.Reasons) == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Message, strings.Join(e.Reasons, ", "))
+}
+
+// tryGetPasswordNotAllowedError returns a PasswordNotAllowedError when the
+// response rejects the password of the given passwordless User, whatever its
+// status. Otherwise the response body is left untouched.
+func tryGetPasswordNotAllowedError(r *http.Response, user string) error {
+	if r.StatusCode < 400 || r.StatusCode >= 500 {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Code != passwordNotAllowedCode {
+		return nil
+	}
+
+	return PasswordNotAllowedError{
+		User: user,
+		Err:  workos_errors.TryGetHTTPError(r),
+	}
+}
+
+// tryGetPasswordStrengthError returns a PasswordStrengthError when the
+// response rejects a password. Otherwise the response body is left untouched.
+func tryGetPasswordStrengthError(r *http.Response) error {
+	if r.StatusCode < 400 || r.StatusCode >= 500 {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var payload struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+		Suggestions []string `json:"suggestions"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil
+	}
+
+	if payload.Code != "password_strength_error" {
+		return nil
+	}
+
+	passwordErr := PasswordStrengthError{
+		Message:     payload.Message,
+		Suggestions: payload.Suggestions,
+	}
+	for _, e := range payload.Errors {
+		reason := e.Message
+		if reason == "" {
+			reason = e.Code
+		}
+		passwordErr.Reasons = append(passwordErr.Reasons, reason)
+	}
+
+	return passwordErr
+}
+
 // SendMagicAuthCode creates a one-time Magic Auth code and emails it to the user.
 func (c *Client) SendMagicAuthCode(ctx context.Context, opts SendMagicAuthCodeOpts) error {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/magic_auth/send",
 		c.Endpoint,
@@ -1309,11 +2341,12 @@ func (c *Client) SendMagicAuthCode(ctx context.Context, opts SendMagicAuthCodeOp
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
@@ -1324,6 +2357,8 @@ func (c *Client) SendMagicAuthCode(ctx context.Context, opts SendMagicAuthCodeOp
 
 // EnrollAuthFactor enrolls an authentication factor for the user.
 func (c *Client) EnrollAuthFactor(ctx context.Context, opts EnrollAuthFactorOpts) (EnrollAuthFactorResponse, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/auth_factors",
 		c.Endpoint,
@@ -1344,11 +2379,12 @@ func (c *Client) EnrollAuthFactor(ctx context.Context, opts EnrollAuthFactorOpts
 		return EnrollAuthFactorResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return EnrollAuthFactorResponse{}, err
 	}
@@ -1367,6 +2403,8 @@ func (c *Client) EnrollAuthFactor(ctx context.Context, opts EnrollAuthFactorOpts
 
 // ListAuthFactors lists the available authentication factors for the user.
 func (c *Client) ListAuthFactors(ctx context.Context, opts ListAuthFactorsOpts) (ListAuthFactorsResponse, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/users/%s/auth_factors",
 		c.Endpoint,
@@ -1382,11 +2420,12 @@ func (c *Client) ListAuthFactors(ctx context.Context, opts ListAuthFactorsOpts)
 		return ListAuthFactorsResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return ListAuthFactorsResponse{}, err
 	}
@@ -1405,6 +2444,8 @@ func (c *Client) ListAuthFactors(ctx context.Context, opts ListAuthFactorsOpts)
 
 // GetOrganizationMembership returns details of an existing Organization Membership
 func (c *Client) GetOrganizationMembership(ctx context.Context, opts GetOrganizationMembershipOpts) (OrganizationMembership, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships/%s",
 		c.Endpoint,
@@ -1420,11 +2461,12 @@ func (c *Client) GetOrganizationMembership(ctx context.Context, opts GetOrganiza
 		return OrganizationMembership{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return OrganizationMembership{}, err
 	}
@@ -1443,6 +2485,8 @@ func (c *Client) GetOrganizationMembership(ctx context.Context, opts GetOrganiza
 
 // List Organization Memberships matching the criteria specified.
 func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrganizationMembershipsOpts) (ListOrganizationMembershipsResponse, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships",
 		c.Endpoint,
@@ -1457,13 +2501,12 @@ func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrgan
 		return ListOrganizationMembershipsResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	if opts.Order == "" {
 		opts.Order = Desc
@@ -1476,7 +2519,7 @@ func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrgan
 
 	req.URL.RawQuery = queryValues.Encode()
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return ListOrganizationMembershipsResponse{}, err
 	}
@@ -1495,6 +2538,8 @@ func (c *Client) ListOrganizationMemberships(ctx context.Context, opts ListOrgan
 
 // Create an Organization Membership. Adds a User to an Organization.
 func (c *Client) CreateOrganizationMembership(ctx context.Context, opts CreateOrganizationMembershipOpts) (OrganizationMembership, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships",
 		c.Endpoint,
@@ -1514,11 +2559,12 @@ func (c *Client) CreateOrganizationMembership(ctx context.Context, opts CreateOr
 		return OrganizationMembership{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return OrganizationMembership{}, err
 	}
@@ -1535,8 +2581,50 @@ func (c *Client) CreateOrganizationMembership(ctx context.Context, opts CreateOr
 	return body, err
 }
 
+// CreateOrganizationMemberships adds several Users to an Organization with
+// bounded concurrency. The results are in the order of opts.Members and carry
+// the error of each membership creation.
+func (c *Client) CreateOrganizationMemberships(ctx context.Context, opts CreateOrganizationMembershipsOpts) []CreateOrganizationMembershipResult {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultMembershipConcurrency
+	}
+
+	results := make([]CreateOrganizationMembershipResult, len(opts.Members))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, member := range opts.Members {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, member MembershipRequest) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			membership, err := c.CreateOrganizationMembership(ctx, CreateOrganizationMembershipOpts{
+				UserID:         member.UserID,
+				OrganizationID: opts.OrganizationID,
+				RoleSlug:       member.RoleSlug,
+			})
+			results[i] = CreateOrganizationMembershipResult{
+				UserID:                 member.UserID,
+				OrganizationMembership: membership,
+				Err:                    err,
+			}
+		}(i, member)
+	}
+
+	wg.Wait()
+	return results
+}
+
 // Delete an Organization Membership. Removes the membership's User from its Organization.
 func (c *Client) DeleteOrganizationMembership(ctx context.Context, opts DeleteOrganizationMembershipOpts) error {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships/%s",
 		c.Endpoint,
@@ -1552,11 +2640,12 @@ func (c *Client) DeleteOrganizationMembership(ctx context.Context, opts DeleteOr
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
@@ -1565,12 +2654,117 @@ func (c *Client) DeleteOrganizationMembership(ctx context.Context, opts DeleteOr
 	return workos_errors.TryGetHTTPError(res)
 }
 
+// RemoveUserFromAllOrganizations deletes every Organization Membership of a
+// User. A failed removal does not prevent the others from being attempted;
+// the failures are returned as MembershipErrors.
+func (c *Client) RemoveUserFromAllOrganizations(ctx context.Context, opts RemoveUserFromAllOrganizationsOpts) error {
+	if opts.User == "" {
+		return errors.New("incomplete arguments: missing User")
+	}
+
+	memberships, err := c.listAllUserOrganizationMemberships(ctx, opts.User)
+	if err != nil {
+		return err
+	}
+
+	var errs MembershipErrors
+	for _, membership := range memberships {
+		err := c.DeleteOrganizationMembership(ctx, DeleteOrganizationMembershipOpts{
+			OrganizationMembership: membership.ID,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", membership.OrganizationID, err))
+		}
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
+	return nil
+}
+
+// listAllUserOrganizationMemberships pages through all the Organization
+// Memberships of the given User.
+func (c *Client) listAllUserOrganizationMemberships(ctx context.Context, userID string) ([]OrganizationMembership, error) {
+	var memberships []OrganizationMembership
+	listOpts := ListOrganizationMembershipsOpts{
+		UserID: userID,
+		Limit:  common.MaxLimit,
+	}
+	for {
+		res, err := c.ListOrganizationMemberships(ctx, listOpts)
+		if err != nil {
+			return nil, err
+		}
+		memberships = append(memberships, res.Data...)
+
+		if res.ListMetadata.After == "" {
+			return memberships, nil
+		}
+		listOpts.After = res.ListMetadata.After
+	}
+}
+
+// UserWithMemberships contains a User and their Organization Memberships.
+type UserWithMemberships struct {
+	// The User.
+	User User `json:"user"`
+
+	// All the Organization Memberships of the User.
+	OrganizationMemberships []OrganizationMembership `json:"organization_memberships"`
+}
+
+// GetUserWithMemberships gets a User and all their Organization Memberships.
+// WorkOS cannot expand memberships on the User, so both are requested
+// concurrently.
+func (c *Client) GetUserWithMemberships(ctx context.Context, opts GetUserOpts) (UserWithMemberships, error) {
+	if opts.User == "" {
+		return UserWithMemberships{}, errors.New("incomplete arguments: missing User")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var memberships []OrganizationMembership
+	var membershipsErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		memberships, membershipsErr = c.listAllUserOrganizationMemberships(ctx, opts.User)
+		if membershipsErr != nil {
+			cancel()
+		}
+	}()
+
+	user, err := c.GetUser(ctx, opts)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		cancel()
+	}
+
+	<-done
+	if membershipsErr != nil && (err == nil || errors.Is(err, context.Canceled)) {
+		// The User request was cancelled because listing the memberships
+		// failed first.
+		return UserWithMemberships{}, membershipsErr
+	}
+	if err != nil {
+		return UserWithMemberships{}, err
+	}
+
+	return UserWithMemberships{
+		User:                    user,
+		OrganizationMemberships: memberships,
+	}, nil
+}
+
 // Update an Organization Membership
 func (c *Client) UpdateOrganizationMembership(
 	ctx context.Context,
 	organizationMembershipId string,
 	opts UpdateOrganizationMembershipOpts,
 ) (OrganizationMembership, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/organization_memberships/%s",
 		c.Endpoint,
@@ -1591,11 +2785,79 @@ func (c *Client) UpdateOrganizationMembership(
 		return OrganizationMembership{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
+	if err != nil {
+		return OrganizationMembership{}, err
+	}
+	defer res.Body.Close()
+
+	if err = workos_errors.TryGetHTTPError(res); err != nil {
+		return OrganizationMembership{}, err
+	}
+
+	var body OrganizationMembership
+	dec := json.NewDecoder(res.Body)
+	err = dec.Decode(&body)
+
+	return body, err
+}
+
+// ActivateOrganizationMembership activates a pending or inactive
+// OrganizationMembership, e.g. once its User accepted an invitation.
+func (c *Client) ActivateOrganizationMembership(
+	ctx context.Context,
+	opts ActivateOrganizationMembershipOpts,
+) (OrganizationMembership, error) {
+	return c.setOrganizationMembershipStatus(ctx, opts.OrganizationMembership, "reactivate")
+}
+
+// DeactivateOrganizationMembership deactivates an OrganizationMembership. The
+// User keeps the membership but can no longer sign in to the Organization.
+func (c *Client) DeactivateOrganizationMembership(
+	ctx context.Context,
+	opts DeactivateOrganizationMembershipOpts,
+) (OrganizationMembership, error) {
+	return c.setOrganizationMembershipStatus(ctx, opts.OrganizationMembership, "deactivate")
+}
+
+func (c *Client) setOrganizationMembershipStatus(
+	ctx context.Context,
+	organizationMembershipId string,
+	action string,
+) (OrganizationMembership, error) {
+	c.once.Do(c.init)
+
+	if organizationMembershipId == "" {
+		return OrganizationMembership{}, errors.New("incomplete arguments: missing OrganizationMembership")
+	}
+
+	endpoint := fmt.Sprintf(
+		"%s/user_management/organization_memberships/%s/%s",
+		c.Endpoint,
+		organizationMembershipId,
+		action,
+	)
+
+	req, err := http.NewRequest(
+		http.MethodPut,
+		endpoint,
+		nil,
+	)
+	if err != nil {
+		return OrganizationMembership{}, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return OrganizationMembership{}, err
 	}
@@ -1614,6 +2876,8 @@ func (c *Client) UpdateOrganizationMembership(
 
 // GetInvitation fetches an Invitation by its ID.
 func (c *Client) GetInvitation(ctx context.Context, opts GetInvitationOpts) (Invitation, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf("%s/user_management/invitations/%s", c.Endpoint, opts.Invitation)
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
@@ -1621,11 +2885,12 @@ func (c *Client) GetInvitation(ctx context.Context, opts GetInvitationOpts) (Inv
 		return Invitation{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return Invitation{}, err
 	}
@@ -1644,6 +2909,8 @@ func (c *Client) GetInvitation(ctx context.Context, opts GetInvitationOpts) (Inv
 
 // ListInvitations gets a list of all of your existing Invitations matching the criteria specified.
 func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts) (ListInvitationsResponse, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf(
 		"%s/user_management/invitations",
 		c.Endpoint,
@@ -1658,13 +2925,12 @@ func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts)
 		return ListInvitationsResponse{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	if opts.Limit == 0 {
-		opts.Limit = ResponseLimit
-	}
+	opts.Limit = common.ClampLimit(opts.Limit, ResponseLimit)
 
 	if opts.Order == "" {
 		opts.Order = Desc
@@ -1677,7 +2943,7 @@ func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts)
 
 	req.URL.RawQuery = queryValues.Encode()
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return ListInvitationsResponse{}, err
 	}
@@ -1695,6 +2961,8 @@ func (c *Client) ListInvitations(ctx context.Context, opts ListInvitationsOpts)
 }
 
 func (c *Client) SendInvitation(ctx context.Context, opts SendInvitationOpts) (Invitation, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf("%s/user_management/invitations", c.Endpoint)
 
 	data, err := json.Marshal(opts)
@@ -1711,11 +2979,12 @@ func (c *Client) SendInvitation(ctx context.Context, opts SendInvitationOpts) (I
 		return Invitation{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return Invitation{}, err
 	}
@@ -1733,6 +3002,8 @@ func (c *Client) SendInvitation(ctx context.Context, opts SendInvitationOpts) (I
 }
 
 func (c *Client) RevokeInvitation(ctx context.Context, opts RevokeInvitationOpts) (Invitation, error) {
+	c.once.Do(c.init)
+
 	endpoint := fmt.Sprintf("%s/user_management/invitations/%s/revoke", c.Endpoint, opts.Invitation)
 
 	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
@@ -1740,11 +3011,12 @@ func (c *Client) RevokeInvitation(ctx context.Context, opts RevokeInvitationOpts
 		return Invitation{}, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return Invitation{}, err
 	}
@@ -1762,6 +3034,8 @@ func (c *Client) RevokeInvitation(ctx context.Context, opts RevokeInvitationOpts
 }
 
 func (c *Client) GetJWKSURL(clientID string) (*url.URL, error) {
+	c.once.Do(c.init)
+
 	if clientID == "" {
 		return nil, errors.New("clientID must not be blank")
 	}
@@ -1783,6 +3057,8 @@ type GetLogoutURLOpts struct {
 }
 
 func (c *Client) GetLogoutURL(opts GetLogoutURLOpts) (*url.URL, error) {
+	c.once.Do(c.init)
+
 	if opts.SessionID == "" {
 		return nil, errors.New("incomplete arguments: missing SessionID")
 	}
@@ -1800,6 +3076,8 @@ func (c *Client) GetLogoutURL(opts GetLogoutURLOpts) (*url.URL, error) {
 }
 
 func (c *Client) RevokeSession(ctx context.Context, opts RevokeSessionOpts) error {
+	c.once.Do(c.init)
+
 	jsonData, err := json.Marshal(opts)
 	if err != nil {
 		return err
@@ -1814,11 +3092,12 @@ func (c *Client) RevokeSession(ctx context.Context, opts RevokeSessionOpts) erro
 		return err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Set("User-Agent", "workos-go/"+workos.Version)
+	req.Header.Set("User-Agent", common.UserAgent())
+	workos.SetCorrelationID(req)
 	req.Header.Set("Authorization", "Bearer "+c.APIKey)
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := workos.Do(c.HTTPClient, c.observer(), c.RequestEditors, req)
 	if err != nil {
 		return err
 	}
@@ -1,19 +1,61 @@
 package usermanagement
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/workos/workos-go/v4/pkg/common"
 	"github.com/workos/workos-go/v4/pkg/mfa"
+	"github.com/workos/workos-go/v4/pkg/workos_errors"
 )
 
+func TestNewClient(t *testing.T) {
+	t.Run("defaults are applied", func(t *testing.T) {
+		client := NewClient("test")
+
+		require.Equal(t, "test", client.APIKey)
+		require.Equal(t, "https://api.workos.com", client.Endpoint)
+		require.Equal(t, 10*time.Second, client.HTTPClient.Timeout)
+	})
+
+	t.Run("options are applied", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(getUserTestHandler))
+		defer server.Close()
+
+		var logs bytes.Buffer
+		httpClient := server.Client()
+		client := NewClient(
+			"test",
+			WithTimeout(time.Minute),
+			WithHTTPClient(httpClient),
+			WithEndpoint(server.URL),
+			WithLogger(log.New(&logs, "", 0)),
+		)
+
+		require.Equal(t, server.URL, client.Endpoint)
+		require.Equal(t, time.Minute, client.HTTPClient.Timeout)
+		require.Zero(t, httpClient.Timeout)
+
+		user, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+		require.NoError(t, err)
+		require.Equal(t, "user_01E3JC5F5Z1YJNPGVYWV9SX6GH", user.ID)
+		require.Contains(t, logs.String(), "workos: /user_management/users/{id}: status 200")
+	})
+}
+
 func TestGetUser(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -39,8 +81,8 @@ func TestGetUser(t *testing.T) {
 				FirstName:     "Marcelina",
 				LastName:      "Davis",
 				EmailVerified: true,
-				CreatedAt:     "2021-06-25T19:07:33.155Z",
-				UpdatedAt:     "2021-06-25T19:07:33.155Z",
+				CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 			},
 		},
 		{
@@ -56,8 +98,8 @@ func TestGetUser(t *testing.T) {
 				LastName:          "Davis",
 				EmailVerified:     true,
 				ProfilePictureURL: "https://workoscdn.com/images/v1/123abc",
-				CreatedAt:         "2021-06-25T19:07:33.155Z",
-				UpdatedAt:         "2021-06-25T19:07:33.155Z",
+				CreatedAt:         time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt:         time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 			},
 		},
 	}
@@ -99,8 +141,8 @@ func getUserTestHandler(w http.ResponseWriter, r *http.Request) {
 			FirstName:     "Marcelina",
 			LastName:      "Davis",
 			EmailVerified: true,
-			CreatedAt:     "2021-06-25T19:07:33.155Z",
-			UpdatedAt:     "2021-06-25T19:07:33.155Z",
+			CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+			UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 		})
 	}
 
@@ -112,8 +154,8 @@ func getUserTestHandler(w http.ResponseWriter, r *http.Request) {
 			LastName:          "Davis",
 			EmailVerified:     true,
 			ProfilePictureURL: "https://workoscdn.com/images/v1/123abc",
-			CreatedAt:         "2021-06-25T19:07:33.155Z",
-			UpdatedAt:         "2021-06-25T19:07:33.155Z",
+			CreatedAt:         time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+			UpdatedAt:         time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 		})
 	}
 
@@ -144,8 +186,8 @@ func TestListUsers(t *testing.T) {
 					FirstName:     "Marcelina",
 					LastName:      "Davis",
 					EmailVerified: true,
-					CreatedAt:     "2021-06-25T19:07:33.155Z",
-					UpdatedAt:     "2021-06-25T19:07:33.155Z",
+					CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+					UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 				},
 			},
 			ListMetadata: common.ListMetadata{
@@ -176,8 +218,8 @@ func TestListUsers(t *testing.T) {
 					FirstName:     "Marcelina",
 					LastName:      "Davis",
 					EmailVerified: true,
-					CreatedAt:     "2021-06-25T19:07:33.155Z",
-					UpdatedAt:     "2021-06-25T19:07:33.155Z",
+					CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+					UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 				},
 			},
 			ListMetadata: common.ListMetadata{
@@ -215,8 +257,8 @@ func TestListUsers(t *testing.T) {
 					FirstName:     "Marcelina",
 					LastName:      "Davis",
 					EmailVerified: true,
-					CreatedAt:     "2021-06-25T19:07:33.155Z",
-					UpdatedAt:     "2021-06-25T19:07:33.155Z",
+					CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+					UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 				},
 			},
 			ListMetadata: common.ListMetadata{
@@ -254,8 +296,8 @@ func listUsersTestHandler(w http.ResponseWriter, r *http.Request) {
 					FirstName:     "Marcelina",
 					LastName:      "Davis",
 					EmailVerified: true,
-					CreatedAt:     "2021-06-25T19:07:33.155Z",
-					UpdatedAt:     "2021-06-25T19:07:33.155Z",
+					CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+					UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 				},
 			},
 			ListMetadata: common.ListMetadata{
@@ -272,6 +314,738 @@ func listUsersTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+func TestGetUserCorrelationID(t *testing.T) {
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		w.Write([]byte(`{"id":"user_123"}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	ctx := common.WithCorrelationID(context.Background(), "corr_123")
+	_, err := client.GetUser(ctx, GetUserOpts{User: "user_123"})
+	require.NoError(t, err)
+	require.Equal(t, "corr_123", header.Get("X-Correlation-ID"))
+
+	_, err = client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+	require.NoError(t, err)
+	require.Empty(t, header.Get("X-Correlation-ID"))
+}
+
+type recordingObserver struct {
+	endpoints []string
+	statuses  []int
+}
+
+func (o *recordingObserver) ObserveRequest(endpoint string, status int, dur time.Duration) {
+	o.endpoints = append(o.endpoints, endpoint)
+	o.statuses = append(o.statuses, status)
+}
+
+func TestObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user_management/users/user_404" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"user_123"}`))
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL), WithObserver(observer))
+
+	_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+	require.NoError(t, err)
+
+	_, err = client.GetUser(context.Background(), GetUserOpts{User: "user_404"})
+	require.Error(t, err)
+
+	require.Equal(t, []string{"/user_management/users/{id}", "/user_management/users/{id}"}, observer.endpoints)
+	require.Equal(t, []int{http.StatusOK, http.StatusNotFound}, observer.statuses)
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/user_management/users" {
+			w.Write([]byte(`{"data":[{"id":"user_123","email":"Foo@Example.com"}],"listMetadata":{}}`))
+			return
+		}
+		w.Write([]byte(`{"id":"user_123","email":"Foo@Example.com"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		scenario       string
+		normalizeEmail bool
+		expected       string
+	}{
+		{scenario: "email is untouched when disabled", expected: "Foo@Example.com"},
+		{scenario: "email is lowercased when enabled", normalizeEmail: true, expected: "foo@example.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+			client.NormalizeEmail = test.normalizeEmail
+
+			user, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, user.Email)
+
+			users, err := client.ListUsers(context.Background(), ListUsersOpts{})
+			require.NoError(t, err)
+			require.Equal(t, test.expected, users.Data[0].Email)
+		})
+	}
+}
+
+func TestListUsersOrder(t *testing.T) {
+	users := []User{
+		{ID: "user_01", CreatedAt: time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC)},
+		{ID: "user_02", CreatedAt: time.Date(2021, time.June, 26, 19, 7, 33, 155000000, time.UTC)},
+		{ID: "user_03", CreatedAt: time.Date(2021, time.June, 27, 19, 7, 33, 155000000, time.UTC)},
+	}
+
+	var order string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = r.URL.Query().Get("order")
+
+		data := make([]User, len(users))
+		copy(data, users)
+		if order != string(Asc) {
+			for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+				data[i], data[j] = data[j], data[i]
+			}
+		}
+
+		body, _ := json.Marshal(ListUsersResponse{Data: data})
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	t.Run("ListUsers sends the requested order", func(t *testing.T) {
+		res, err := client.ListUsers(context.Background(), ListUsersOpts{Order: Asc})
+		require.NoError(t, err)
+		require.Equal(t, "asc", order)
+		require.Equal(t, users, res.Data)
+	})
+
+	t.Run("ListUsers defaults to descending order", func(t *testing.T) {
+		res, err := client.ListUsers(context.Background(), ListUsersOpts{})
+		require.NoError(t, err)
+		require.Equal(t, "desc", order)
+		require.Equal(t, "user_03", res.Data[0].ID)
+	})
+}
+
+func TestGetUserByEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(getUserByEmailTestHandler))
+	defer server.Close()
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	t.Run("GetUserByEmail returns the matching User", func(t *testing.T) {
+		user, found, err := client.GetUserByEmail(context.Background(), GetUserByEmailOpts{
+			Email: "marcelina@foo-corp.com",
+		})
+
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "user_01E3JC5F5Z1YJNPGVYWV9SX6GH", user.ID)
+	})
+
+	t.Run("GetUserByEmail returns not found when no User matches", func(t *testing.T) {
+		user, found, err := client.GetUserByEmail(context.Background(), GetUserByEmailOpts{
+			Email: "unknown@foo-corp.com",
+		})
+
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Equal(t, User{}, user)
+	})
+
+	t.Run("GetUserByEmail requires an email", func(t *testing.T) {
+		_, _, err := client.GetUserByEmail(context.Background(), GetUserByEmailOpts{})
+		require.Error(t, err)
+	})
+}
+
+func TestGetUserBySSOProfile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("email") != "marcelina@foo-corp.com" || q.Get("organization_id") != "org_123" {
+			w.Write([]byte(`{"data":[],"listMetadata":{}}`))
+			return
+		}
+		w.Write([]byte(`{
+			"data": [{"id": "user_01E3JC5F5Z1YJNPGVYWV9SX6GH", "email": "marcelina@foo-corp.com"}],
+			"listMetadata": {}
+		}`))
+	}))
+	defer server.Close()
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	t.Run("GetUserBySSOProfile returns the member matching the Profile email", func(t *testing.T) {
+		user, found, err := client.GetUserBySSOProfile(context.Background(), GetUserBySSOProfileOpts{
+			Email:          "marcelina@foo-corp.com",
+			OrganizationID: "org_123",
+		})
+
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "user_01E3JC5F5Z1YJNPGVYWV9SX6GH", user.ID)
+	})
+
+	t.Run("GetUserBySSOProfile returns not found when no User matches", func(t *testing.T) {
+		user, found, err := client.GetUserBySSOProfile(context.Background(), GetUserBySSOProfileOpts{
+			Email:          "unknown@foo-corp.com",
+			OrganizationID: "org_123",
+		})
+
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Equal(t, User{}, user)
+	})
+
+	t.Run("GetUserBySSOProfile does not match Users of another Organization", func(t *testing.T) {
+		user, found, err := client.GetUserBySSOProfile(context.Background(), GetUserBySSOProfileOpts{
+			Email:          "marcelina@foo-corp.com",
+			OrganizationID: "org_456",
+		})
+
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Equal(t, User{}, user)
+	})
+
+	t.Run("GetUserBySSOProfile requires an email and an Organization", func(t *testing.T) {
+		_, _, err := client.GetUserBySSOProfile(context.Background(), GetUserBySSOProfileOpts{})
+		require.EqualError(t, err, "incomplete arguments: missing Email")
+
+		_, _, err = client.GetUserBySSOProfile(context.Background(), GetUserBySSOProfileOpts{
+			Email: "marcelina@foo-corp.com",
+		})
+		require.EqualError(t, err, "incomplete arguments: missing OrganizationID")
+	})
+}
+
+func getUserByEmailTestHandler(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if auth != "Bearer test" {
+		http.Error(w, "bad auth", http.StatusUnauthorized)
+		return
+	}
+
+	response := ListUsersResponse{Data: []User{}}
+	if r.URL.Query().Get("email") == "marcelina@foo-corp.com" {
+		response.Data = append(response.Data, User{
+			ID:    "user_01E3JC5F5Z1YJNPGVYWV9SX6GH",
+			Email: "marcelina@foo-corp.com",
+		})
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
+
+func TestUserMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var opts struct {
+			Email    string            `json:"email"`
+			Metadata map[string]string `json:"metadata"`
+		}
+		json.NewDecoder(r.Body).Decode(&opts)
+
+		body, _ := json.Marshal(User{
+			ID:       "user_01E3JC5F5Z1YJNPGVYWV9SX6GH",
+			Email:    opts.Email,
+			Metadata: opts.Metadata,
+		})
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	t.Run("CreateUser sends the metadata", func(t *testing.T) {
+		user, err := client.CreateUser(context.Background(), CreateUserOpts{
+			Email:    "marcelina@foo-corp.com",
+			Metadata: map[string]string{"onboarding_step": "1"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"onboarding_step": "1"}, user.Metadata)
+	})
+
+	t.Run("UpdateUser sends the metadata", func(t *testing.T) {
+		user, err := client.UpdateUser(context.Background(), UpdateUserOpts{
+			User:     "user_01E3JC5F5Z1YJNPGVYWV9SX6GH",
+			Metadata: map[string]string{"onboarding_step": "2", "beta": "true"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"onboarding_step": "2", "beta": "true"}, user.Metadata)
+	})
+
+	t.Run("metadata exceeding the API limits is rejected", func(t *testing.T) {
+		tooManyKeys := map[string]string{}
+		for i := 0; i <= MaxMetadataKeys; i++ {
+			tooManyKeys[fmt.Sprintf("key_%d", i)] = "value"
+		}
+
+		for _, metadata := range []map[string]string{
+			tooManyKeys,
+			{strings.Repeat("k", MaxMetadataKeyLength+1): "value"},
+			{"key": strings.Repeat("v", MaxMetadataValueLength+1)},
+		} {
+			_, err := client.CreateUser(context.Background(), CreateUserOpts{
+				Email:    "marcelina@foo-corp.com",
+				Metadata: metadata,
+			})
+			require.Error(t, err)
+
+			_, err = client.UpdateUser(context.Background(), UpdateUserOpts{
+				User:     "user_01E3JC5F5Z1YJNPGVYWV9SX6GH",
+				Metadata: metadata,
+			})
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestListUsersFilters(t *testing.T) {
+	var rawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		w.Write([]byte(`{"data":[],"listMetadata":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	_, err := client.ListUsers(context.Background(), ListUsersOpts{
+		Email:          "marcelina@foo-corp.com",
+		OrganizationID: "org_123",
+		Limit:          5,
+		Order:          Asc,
+		After:          "user_123",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "after=user_123&email=marcelina%40foo-corp.com&limit=5&order=asc&organization_id=org_123", rawQuery)
+}
+
+func TestListUsersCursorValidation(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Write([]byte(`{"data":[],"list_metadata":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	_, err := client.ListUsers(context.Background(), ListUsersOpts{After: "user_01E3JC5F5Z1YJNPGVYWV9SX6GH"})
+	require.NoError(t, err)
+
+	_, err = client.ListUsers(context.Background(), ListUsersOpts{Before: " "})
+	require.Equal(t, common.CursorError{Param: "before", Cursor: " ", Reason: "cursor is blank"}, err)
+
+	_, err = client.ListUsers(context.Background(), ListUsersOpts{After: "user_123\r\n"})
+	require.IsType(t, common.CursorError{}, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestClientNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+
+	_, err = client.GetOrganizationMembership(context.Background(), GetOrganizationMembershipOpts{OrganizationMembership: "om_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+
+	_, err = client.GetInvitation(context.Background(), GetInvitationOpts{Invitation: "invitation_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+
+	err = client.DeleteUser(context.Background(), DeleteUserOpts{User: "user_unknown"})
+	require.True(t, errors.Is(err, workos_errors.ErrNotFound))
+}
+
+func TestClientEndpointTrailingSlash(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"id":"user_123"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL+"/"))
+
+	_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+	require.NoError(t, err)
+	require.Equal(t, "/user_management/users/user_123", path)
+}
+
+func TestForEachUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("after") == "" {
+			w.Write([]byte(`{"data":[{"id":"user_1"},{"id":"user_2"},{"id":"user_3"}],"list_metadata":{"after":"user_3"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"user_4"},{"id":"user_5"}],"list_metadata":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	t.Run("every User is processed across pages", func(t *testing.T) {
+		var calls int32
+		var mu sync.Mutex
+		var ids []string
+
+		err := client.ForEachUser(context.Background(), ForEachUserOpts{Concurrency: 3}, func(ctx context.Context, u User) error {
+			atomic.AddInt32(&calls, 1)
+			mu.Lock()
+			ids = append(ids, u.ID)
+			mu.Unlock()
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(5), atomic.LoadInt32(&calls))
+		require.ElementsMatch(t, []string{"user_1", "user_2", "user_3", "user_4", "user_5"}, ids)
+	})
+
+	t.Run("the first error stops the processing", func(t *testing.T) {
+		var calls int32
+		err := client.ForEachUser(context.Background(), ForEachUserOpts{Concurrency: 1}, func(ctx context.Context, u User) error {
+			atomic.AddInt32(&calls, 1)
+			if u.ID == "user_2" {
+				return fmt.Errorf("failed %s", u.ID)
+			}
+			return nil
+		})
+		require.EqualError(t, err, "failed user_2")
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("errors are collected with ContinueOnError", func(t *testing.T) {
+		var calls int32
+		err := client.ForEachUser(context.Background(), ForEachUserOpts{Concurrency: 2, ContinueOnError: true}, func(ctx context.Context, u User) error {
+			atomic.AddInt32(&calls, 1)
+			if u.ID == "user_2" || u.ID == "user_4" {
+				return fmt.Errorf("failed %s", u.ID)
+			}
+			return nil
+		})
+		require.Error(t, err)
+		require.Len(t, err.(UserErrors), 2)
+		require.Equal(t, int32(5), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestStreamUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("after") == "" {
+			w.Write([]byte(`{"data":[{"id":"user_1"},{"id":"user_2"}],"list_metadata":{"after":"user_2"}}`))
+			return
+		}
+		w.Write([]byte(`{"data":[{"id":"user_3"}],"list_metadata":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	t.Run("Users of every page are streamed", func(t *testing.T) {
+		users, errs := client.StreamUsers(context.Background(), ListUsersOpts{})
+
+		var ids []string
+		for u := range users {
+			ids = append(ids, u.ID)
+		}
+		require.Equal(t, []string{"user_1", "user_2", "user_3"}, ids)
+
+		err, ok := <-errs
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("cancelling the context stops the stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		users, errs := client.StreamUsers(ctx, ListUsersOpts{})
+
+		u := <-users
+		require.Equal(t, "user_1", u.ID)
+		cancel()
+
+		for range users {
+		}
+		require.True(t, errors.Is(<-errs, context.Canceled))
+		_, ok := <-errs
+		require.False(t, ok)
+	})
+}
+
+func TestOrganizationMembershipActivation(t *testing.T) {
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+
+		status := "active"
+		if strings.HasSuffix(r.URL.Path, "/deactivate") {
+			status = "inactive"
+		}
+		fmt.Fprintf(w, `{"id":"om_123","user_id":"user_123","organization_id":"org_123","status":%q}`, status)
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	membership, err := client.ActivateOrganizationMembership(context.Background(), ActivateOrganizationMembershipOpts{
+		OrganizationMembership: "om_123",
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPut, method)
+	require.Equal(t, "/user_management/organization_memberships/om_123/reactivate", path)
+	require.Equal(t, Active, membership.Status)
+
+	membership, err = client.DeactivateOrganizationMembership(context.Background(), DeactivateOrganizationMembershipOpts{
+		OrganizationMembership: "om_123",
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPut, method)
+	require.Equal(t, "/user_management/organization_memberships/om_123/deactivate", path)
+	require.Equal(t, InactiveOrganizationMembership, membership.Status)
+
+	_, err = client.ActivateOrganizationMembership(context.Background(), ActivateOrganizationMembershipOpts{})
+	require.EqualError(t, err, "incomplete arguments: missing OrganizationMembership")
+}
+
+func TestRemoveUserFromAllOrganizations(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			require.Equal(t, "user_123", r.URL.Query().Get("user_id"))
+			w.Write([]byte(`{"data":[
+				{"id":"om_1","user_id":"user_123","organization_id":"org_1"},
+				{"id":"om_2","user_id":"user_123","organization_id":"org_2"}
+			],"list_metadata":{}}`))
+		case http.MethodDelete:
+			mu.Lock()
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/user_management/organization_memberships/"))
+			mu.Unlock()
+			if strings.HasSuffix(r.URL.Path, "om_1") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	err := client.RemoveUserFromAllOrganizations(context.Background(), RemoveUserFromAllOrganizationsOpts{
+		User: "user_123",
+	})
+	require.Error(t, err)
+	require.Len(t, err.(MembershipErrors), 1)
+	require.Contains(t, err.Error(), "organization org_1")
+	require.Equal(t, []string{"om_1", "om_2"}, deleted)
+
+	err = client.RemoveUserFromAllOrganizations(context.Background(), RemoveUserFromAllOrganizationsOpts{})
+	require.EqualError(t, err, "incomplete arguments: missing User")
+}
+
+func TestGetUserWithMemberships(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/user_management/users/user_123":
+			w.Write([]byte(`{"id":"user_123","email":"marcelina@foo-corp.com"}`))
+		case "/user_management/organization_memberships":
+			if r.URL.Query().Get("after") == "" {
+				w.Write([]byte(`{"data":[{"id":"om_1","user_id":"user_123","organization_id":"org_1"}],"list_metadata":{"after":"om_1"}}`))
+				return
+			}
+			w.Write([]byte(`{"data":[{"id":"om_2","user_id":"user_123","organization_id":"org_2"}],"list_metadata":{}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	res, err := client.GetUserWithMemberships(context.Background(), GetUserOpts{User: "user_123"})
+	require.NoError(t, err)
+	require.Equal(t, "marcelina@foo-corp.com", res.User.Email)
+	require.Len(t, res.OrganizationMemberships, 2)
+	require.Equal(t, "org_1", res.OrganizationMemberships[0].OrganizationID)
+	require.Equal(t, "org_2", res.OrganizationMemberships[1].OrganizationID)
+	require.ElementsMatch(t, []string{
+		"/user_management/users/user_123",
+		"/user_management/organization_memberships",
+		"/user_management/organization_memberships",
+	}, paths)
+
+	_, err = client.GetUserWithMemberships(context.Background(), GetUserOpts{User: "user_unknown"})
+	require.Error(t, err)
+
+	_, err = client.GetUserWithMemberships(context.Background(), GetUserOpts{})
+	require.EqualError(t, err, "incomplete arguments: missing User")
+}
+
+func TestGetUserWithMembershipsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user_management/users/user_123":
+			<-r.Context().Done()
+		case "/user_management/organization_memberships":
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	_, err := client.GetUserWithMemberships(context.Background(), GetUserOpts{User: "user_123"})
+
+	var httpErr workos_errors.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusInternalServerError, httpErr.Code)
+}
+
+func TestPing(t *testing.T) {
+	var rawQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rawQuery = r.URL.RawQuery
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"Unauthorized"}`))
+			return
+		}
+		w.Write([]byte(`{"data":[],"listMetadata":{}}`))
+	}))
+	defer server.Close()
+
+	t.Run("Ping succeeds with a valid API key", func(t *testing.T) {
+		client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+		err := client.Ping(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "limit=1&order=desc", rawQuery)
+	})
+
+	t.Run("Ping returns an unauthorized error with an invalid API key", func(t *testing.T) {
+		client := NewClient("invalid", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+		err := client.Ping(context.Background())
+		require.Error(t, err)
+		require.True(t, workos_errors.IsUnauthorized(err))
+	})
+}
+
+func TestGetUserByExternalID(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		if r.URL.Path != "/user_management/users/external_id/f1ffa2b2-c20b-4d39-be5c-212726e11222" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{
+			"id": "user_01E3JC5F5Z1YJNPGVYWV9SX6GH",
+			"email": "marcelina@foo-corp.com",
+			"external_id": "f1ffa2b2-c20b-4d39-be5c-212726e11222"
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+	}
+
+	t.Run("GetUserByExternalID returns the matching User", func(t *testing.T) {
+		user, found, err := client.GetUserByExternalID(context.Background(), GetUserByExternalIDOpts{
+			ExternalID: "f1ffa2b2-c20b-4d39-be5c-212726e11222",
+		})
+
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, User{
+			ID:         "user_01E3JC5F5Z1YJNPGVYWV9SX6GH",
+			Email:      "marcelina@foo-corp.com",
+			ExternalID: "f1ffa2b2-c20b-4d39-be5c-212726e11222",
+		}, user)
+	})
+
+	t.Run("GetUserByExternalID returns not found when no User matches", func(t *testing.T) {
+		user, found, err := client.GetUserByExternalID(context.Background(), GetUserByExternalIDOpts{
+			ExternalID: "unknown",
+		})
+
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Equal(t, User{}, user)
+	})
+
+	t.Run("GetUserByExternalID escapes the external identifier", func(t *testing.T) {
+		_, found, err := client.GetUserByExternalID(context.Background(), GetUserByExternalIDOpts{
+			ExternalID: "a/b",
+		})
+
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Equal(t, "/user_management/users/external_id/a%2Fb", path)
+	})
+
+	t.Run("GetUserByExternalID requires an external identifier", func(t *testing.T) {
+		_, _, err := client.GetUserByExternalID(context.Background(), GetUserByExternalIDOpts{})
+		require.Error(t, err)
+	})
+}
+
 func TestCreateUser(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -301,8 +1075,8 @@ func TestCreateUser(t *testing.T) {
 				FirstName:     "Marcelina",
 				LastName:      "Davis",
 				EmailVerified: true,
-				CreatedAt:     "2021-06-25T19:07:33.155Z",
-				UpdatedAt:     "2021-06-25T19:07:33.155Z",
+				CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 			},
 		},
 	}
@@ -344,8 +1118,8 @@ func createUserTestHandler(w http.ResponseWriter, r *http.Request) {
 			FirstName:     "Marcelina",
 			LastName:      "Davis",
 			EmailVerified: true,
-			CreatedAt:     "2021-06-25T19:07:33.155Z",
-			UpdatedAt:     "2021-06-25T19:07:33.155Z",
+			CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+			UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 		})
 	}
 
@@ -386,8 +1160,8 @@ func TestUpdateUser(t *testing.T) {
 				FirstName:     "Marcelina",
 				LastName:      "Davis",
 				EmailVerified: true,
-				CreatedAt:     "2021-06-25T19:07:33.155Z",
-				UpdatedAt:     "2021-06-25T19:07:33.155Z",
+				CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 			},
 		},
 	}
@@ -429,8 +1203,8 @@ func updateUserTestHandler(w http.ResponseWriter, r *http.Request) {
 			FirstName:     "Marcelina",
 			LastName:      "Davis",
 			EmailVerified: true,
-			CreatedAt:     "2021-06-25T19:07:33.155Z",
-			UpdatedAt:     "2021-06-25T19:07:33.155Z",
+			CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+			UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 		})
 	}
 
@@ -610,6 +1384,28 @@ func TestClientAuthorizeURL(t *testing.T) {
 	}
 }
 
+func TestClientSignUpAndSignInURLs(t *testing.T) {
+	client := NewClient("test")
+	opts := GetAuthorizationURLOpts{
+		ClientID:    "client_123",
+		RedirectURI: "https://example.com/callback",
+	}
+
+	u, err := client.GetSignUpURL(opts)
+	require.NoError(t, err)
+	require.Equal(t, "authkit", u.Query().Get("provider"))
+	require.Equal(t, "sign-up", u.Query().Get("screen_hint"))
+
+	u, err = client.GetSignInURL(opts)
+	require.NoError(t, err)
+	require.Equal(t, "authkit", u.Query().Get("provider"))
+	require.Equal(t, "sign-in", u.Query().Get("screen_hint"))
+
+	opts.Provider = "GoogleOAuth"
+	_, err = client.GetSignUpURL(opts)
+	require.Error(t, err)
+}
+
 func TestClientAuthorizeURLInvalidOpts(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -776,6 +1572,71 @@ func TestAuthenticateUserWithCode(t *testing.T) {
 	}
 }
 
+func TestAuthenticateWithInvitationToken(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["invitation_token"] != "Z1uX3RbwcIl5fIGJJJCXXisdI" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{
+			"user": {"id": "user_123", "email": "marcelina@foo-corp.com"},
+			"organization_id": "org_123",
+			"access_token": "access_token",
+			"refresh_token": "refresh_token"
+		}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	t.Run("password", func(t *testing.T) {
+		res, err := client.AuthenticateWithPassword(context.Background(), AuthenticateWithPasswordOpts{
+			ClientID:        "project_123",
+			Email:           "marcelina@foo-corp.com",
+			Password:        "password",
+			InvitationToken: "Z1uX3RbwcIl5fIGJJJCXXisdI",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "password", body["grant_type"])
+		require.Equal(t, "org_123", res.OrganizationID)
+	})
+
+	t.Run("code", func(t *testing.T) {
+		res, err := client.AuthenticateWithCode(context.Background(), AuthenticateWithCodeOpts{
+			ClientID:        "project_123",
+			Code:            "code",
+			InvitationToken: "Z1uX3RbwcIl5fIGJJJCXXisdI",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "authorization_code", body["grant_type"])
+		require.Equal(t, "org_123", res.OrganizationID)
+	})
+
+	t.Run("Magic Auth", func(t *testing.T) {
+		res, err := client.AuthenticateWithMagicAuth(context.Background(), AuthenticateWithMagicAuthOpts{
+			ClientID:        "project_123",
+			Code:            "123456",
+			Email:           "marcelina@foo-corp.com",
+			InvitationToken: "Z1uX3RbwcIl5fIGJJJCXXisdI",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "org_123", res.OrganizationID)
+		require.Equal(t, "user_123", res.User.ID)
+	})
+
+	t.Run("the token is omitted when empty", func(t *testing.T) {
+		_, err := client.AuthenticateWithCode(context.Background(), AuthenticateWithCodeOpts{
+			ClientID: "project_123",
+			Code:     "code",
+		})
+		require.Error(t, err)
+		require.NotContains(t, body, "invitation_token")
+	})
+}
+
 func TestAuthenticateUserWithRefreshToken(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -1131,8 +1992,8 @@ func TestSendVerificationEmail(t *testing.T) {
 					FirstName:     "Marcelina",
 					LastName:      "Davis",
 					EmailVerified: true,
-					CreatedAt:     "2021-06-25T19:07:33.155Z",
-					UpdatedAt:     "2021-06-25T19:07:33.155Z",
+					CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+					UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 				},
 			},
 		},
@@ -1177,8 +2038,8 @@ func sendVerificationEmailTestHandler(w http.ResponseWriter, r *http.Request) {
 				FirstName:     "Marcelina",
 				LastName:      "Davis",
 				EmailVerified: true,
-				CreatedAt:     "2021-06-25T19:07:33.155Z",
-				UpdatedAt:     "2021-06-25T19:07:33.155Z",
+				CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 			},
 		})
 	}
@@ -1275,6 +2136,58 @@ func verifyEmailCodeTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+func TestEmailLocale(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	t.Run("SendVerificationEmail sends the locale", func(t *testing.T) {
+		_, err := client.SendVerificationEmail(context.Background(), SendVerificationEmailOpts{
+			User:   "user_123",
+			Locale: "fr",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "fr", body["locale"])
+
+		_, err = client.SendVerificationEmail(context.Background(), SendVerificationEmailOpts{
+			User: "user_123",
+		})
+		require.NoError(t, err)
+		require.NotContains(t, body, "locale")
+	})
+
+	t.Run("SendPasswordResetEmail sends the locale", func(t *testing.T) {
+		err := client.SendPasswordResetEmail(context.Background(), SendPasswordResetEmailOpts{
+			Email:            "marcelina@foo-corp.com",
+			PasswordResetUrl: "https://foo-corp.com/reset",
+			Locale:           "pt-BR",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "pt-BR", body["locale"])
+	})
+
+	t.Run("SendMagicAuthCode sends the locale", func(t *testing.T) {
+		err := client.SendMagicAuthCode(context.Background(), SendMagicAuthCodeOpts{
+			Email:  "marcelina@foo-corp.com",
+			Locale: "de",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "de", body["locale"])
+
+		err = client.SendMagicAuthCode(context.Background(), SendMagicAuthCodeOpts{
+			Email: "marcelina@foo-corp.com",
+		})
+		require.NoError(t, err)
+		require.NotContains(t, body, "locale")
+	})
+}
+
 func TestSendPasswordResetEmail(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -1410,6 +2323,206 @@ func resetPasswordHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+func TestCreateUsers(t *testing.T) {
+	for _, rollback := range []bool{false, true} {
+		t.Run(fmt.Sprintf("rollback %t", rollback), func(t *testing.T) {
+			var created int
+			var deleted []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method {
+				case http.MethodPost:
+					created++
+					if created == 3 {
+						w.Header().Set("Content-Type", "application/json")
+						w.WriteHeader(http.StatusUnprocessableEntity)
+						w.Write([]byte(`{"code":"user_exists","message":"User already exists"}`))
+						return
+					}
+					fmt.Fprintf(w, `{"id":"user_%d"}`, created)
+				case http.MethodDelete:
+					deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/user_management/users/"))
+					w.WriteHeader(http.StatusAccepted)
+				}
+			}))
+			defer server.Close()
+
+			client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+			users, err := client.CreateUsers(context.Background(), CreateUsersOpts{
+				Users: []CreateUserOpts{
+					{Email: "marcelina@foo-corp.com"},
+					{Email: "jon@foo-corp.com"},
+					{Email: "marcelina@foo-corp.com"},
+					{Email: "ada@foo-corp.com"},
+				},
+				RollbackOnError: rollback,
+			})
+			require.Error(t, err)
+			require.Equal(t, 3, created)
+
+			createErr, ok := err.(CreateUsersError)
+			require.True(t, ok)
+			require.Equal(t, 2, createErr.Index)
+			require.Empty(t, createErr.RollbackErrors)
+			require.Contains(t, err.Error(), "user 2: ")
+
+			var httpErr workos_errors.HTTPError
+			require.True(t, errors.As(err, &httpErr))
+			require.Equal(t, http.StatusUnprocessableEntity, httpErr.Code)
+
+			if rollback {
+				require.Equal(t, []string{"user_1", "user_2"}, deleted)
+				require.Empty(t, users)
+				return
+			}
+			require.Empty(t, deleted)
+			require.Len(t, users, 2)
+		})
+	}
+}
+
+func TestCreateUsersRollbackAfterCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var created int
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			created++
+			if created == 2 {
+				cancel()
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprintf(w, `{"id":"user_%d"}`, created)
+		case http.MethodDelete:
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/user_management/users/"))
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	users, err := client.CreateUsers(ctx, CreateUsersOpts{
+		Users: []CreateUserOpts{
+			{Email: "marcelina@foo-corp.com"},
+			{Email: "jon@foo-corp.com"},
+		},
+		RollbackOnError: true,
+	})
+	require.Error(t, err)
+	require.Empty(t, err.(CreateUsersError).RollbackErrors)
+	require.Equal(t, []string{"user_1"}, deleted)
+	require.Empty(t, users)
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/user_management/users/user_passwordless":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"code":"password_not_allowed","message":"User cannot have a password."}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/user_management/users/user_sso":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"password_not_allowed","message":"User cannot have a password."}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/user_management/users/user_123":
+			w.Write([]byte(`{"id":"user_123"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	_, err := client.UpdateUserPassword(context.Background(), UpdateUserPasswordOpts{
+		User:     "user_passwordless",
+		Password: "i8uv6g34kd490s",
+	})
+	var notAllowed PasswordNotAllowedError
+	require.True(t, errors.As(err, &notAllowed))
+	require.Equal(t, "user_passwordless", notAllowed.User)
+	require.EqualError(t, err, "user user_passwordless is passwordless and cannot have a password set")
+
+	var httpErr workos_errors.HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusUnprocessableEntity, httpErr.Code)
+
+	_, err = client.UpdateUserPassword(context.Background(), UpdateUserPasswordOpts{
+		User:     "user_sso",
+		Password: "i8uv6g34kd490s",
+	})
+	require.True(t, errors.As(err, &notAllowed))
+	require.Equal(t, "user_sso", notAllowed.User)
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+	require.Equal(t, "User cannot have a password.", httpErr.Message)
+
+	user, err := client.UpdateUserPassword(context.Background(), UpdateUserPasswordOpts{
+		User:     "user_123",
+		Password: "i8uv6g34kd490s",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "user_123", user.ID)
+	require.Equal(t, 3, calls)
+
+	_, err = client.UpdateUserPassword(context.Background(), UpdateUserPasswordOpts{User: "user_123"})
+	require.EqualError(t, err, "incomplete arguments: missing Password or PasswordHash")
+}
+
+func TestPasswordStrengthError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{
+			"code": "password_strength_error",
+			"message": "Password does not meet strength requirements.",
+			"errors": [
+				{"code": "password_too_short", "message": "Password must be at least 10 characters."},
+				{"code": "password_breached"}
+			],
+			"suggestions": ["Add another word or two."]
+		}`))
+	}))
+	defer server.Close()
+
+	client := &Client{
+		HTTPClient: server.Client(),
+		Endpoint:   server.URL,
+		APIKey:     "test",
+		JSONEncode: json.Marshal,
+	}
+
+	expected := PasswordStrengthError{
+		Message:     "Password does not meet strength requirements.",
+		Reasons:     []string{"Password must be at least 10 characters.", "password_breached"},
+		Suggestions: []string{"Add another word or two."},
+	}
+
+	t.Run("UpdateUser returns a PasswordStrengthError", func(t *testing.T) {
+		_, err := client.UpdateUser(context.Background(), UpdateUserOpts{
+			User:     "user_123",
+			Password: "weak",
+		})
+		require.Equal(t, expected, err)
+	})
+
+	t.Run("ResetPassword returns a PasswordStrengthError", func(t *testing.T) {
+		_, err := client.ResetPassword(context.Background(), ResetPasswordOpts{
+			Token:       "token",
+			NewPassword: "weak",
+		})
+		require.Equal(t, expected, err)
+	})
+}
+
 func TestSendMagicAuthCode(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -1899,6 +3012,71 @@ func TestCreateOrganizationMembership(t *testing.T) {
 	}
 }
 
+func TestCreateOrganizationMemberships(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if n <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		var opts CreateOrganizationMembershipOpts
+		json.NewDecoder(r.Body).Decode(&opts)
+
+		if opts.UserID == "user_unknown" {
+			http.Error(w, `{"message":"User not found"}`, http.StatusNotFound)
+			return
+		}
+
+		body, _ := json.Marshal(OrganizationMembership{
+			ID:             "om_" + opts.UserID,
+			UserID:         opts.UserID,
+			OrganizationID: opts.OrganizationID,
+			Role:           RoleResponse{Slug: opts.RoleSlug},
+		})
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	results := client.CreateOrganizationMemberships(context.Background(), CreateOrganizationMembershipsOpts{
+		OrganizationID: "org_123",
+		Members: []MembershipRequest{
+			{UserID: "user_1", RoleSlug: "admin"},
+			{UserID: "user_unknown"},
+			{UserID: "user_2"},
+			{UserID: "user_3"},
+		},
+		Concurrency: 2,
+	})
+
+	require.Len(t, results, 4)
+	require.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(2))
+
+	require.NoError(t, results[0].Err)
+	require.Equal(t, OrganizationMembership{
+		ID:             "om_user_1",
+		UserID:         "user_1",
+		OrganizationID: "org_123",
+		Role:           RoleResponse{Slug: "admin"},
+	}, results[0].OrganizationMembership)
+
+	require.Equal(t, "user_unknown", results[1].UserID)
+	require.Error(t, results[1].Err)
+
+	for _, result := range results[2:] {
+		require.NoError(t, result.Err)
+		require.Equal(t, "om_"+result.UserID, result.OrganizationMembership.ID)
+	}
+}
+
 func createOrganizationMembershipTestHandler(w http.ResponseWriter, r *http.Request) {
 	auth := r.Header.Get("Authorization")
 	if auth != "Bearer test" {
@@ -2350,6 +3528,94 @@ func SendInvitationTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(body)
 }
 
+func TestInvitationsAPI(t *testing.T) {
+	var method, path, rawQuery string
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path, rawQuery = r.Method, r.URL.Path, r.URL.RawQuery
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+
+		invitation := `{
+			"id": "invitation_123",
+			"email": "marcelina@foo-corp.com",
+			"state": "pending",
+			"token": "Z1uX3RbwcIl5fIGJJJCXXisdI",
+			"accept_invitation_url": "https://your-app.com/invite?invitation_token=Z1uX3RbwcIl5fIGJJJCXXisdI",
+			"organization_id": "org_123",
+			"inviter_user_id": "user_123",
+			"expires_at": "2021-07-01T19:07:33.155Z",
+			"created_at": "2021-06-25T19:07:33.155Z",
+			"updated_at": "2021-06-25T19:07:33.155Z"
+		}`
+		switch {
+		case r.Method == http.MethodGet && path == "/user_management/invitations":
+			fmt.Fprintf(w, `{"data":[%s],"listMetadata":{}}`, invitation)
+		case strings.HasSuffix(path, "/revoke"):
+			w.Write([]byte(strings.Replace(invitation, `"pending"`, `"revoked"`, 1)))
+		default:
+			w.Write([]byte(invitation))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	expected := Invitation{
+		ID:                  "invitation_123",
+		Email:               "marcelina@foo-corp.com",
+		State:               Pending,
+		Token:               "Z1uX3RbwcIl5fIGJJJCXXisdI",
+		AcceptInvitationURL: "https://your-app.com/invite?invitation_token=Z1uX3RbwcIl5fIGJJJCXXisdI",
+		OrganizationID:      "org_123",
+		InviterUserID:       "user_123",
+		ExpiresAt:           "2021-07-01T19:07:33.155Z",
+		CreatedAt:           "2021-06-25T19:07:33.155Z",
+		UpdatedAt:           "2021-06-25T19:07:33.155Z",
+	}
+
+	t.Run("SendInvitation", func(t *testing.T) {
+		invitation, err := client.SendInvitation(context.Background(), SendInvitationOpts{
+			Email:          "marcelina@foo-corp.com",
+			OrganizationID: "org_123",
+			ExpiresInDays:  6,
+			InviterUserID:  "user_123",
+			RoleSlug:       "admin",
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, invitation)
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/user_management/invitations", path)
+		require.Equal(t, map[string]interface{}{
+			"email":           "marcelina@foo-corp.com",
+			"organization_id": "org_123",
+			"expires_in_days": float64(6),
+			"inviter_user_id": "user_123",
+			"role_slug":       "admin",
+		}, body)
+	})
+
+	t.Run("ListInvitations filters by Organization and email", func(t *testing.T) {
+		res, err := client.ListInvitations(context.Background(), ListInvitationsOpts{
+			OrganizationID: "org_123",
+			Email:          "marcelina@foo-corp.com",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []Invitation{expected}, res.Data)
+		require.Equal(t, "email=marcelina%40foo-corp.com&limit=10&order=desc&organization_id=org_123", rawQuery)
+	})
+
+	t.Run("RevokeInvitation", func(t *testing.T) {
+		invitation, err := client.RevokeInvitation(context.Background(), RevokeInvitationOpts{
+			Invitation: "invitation_123",
+		})
+		require.NoError(t, err)
+		require.Equal(t, http.MethodPost, method)
+		require.Equal(t, "/user_management/invitations/invitation_123/revoke", path)
+		require.Equal(t, Revoked, invitation.State)
+	})
+}
+
 func TestRevokeInvitation(t *testing.T) {
 	tests := []struct {
 		scenario string
@@ -2446,3 +3712,361 @@ func RevokeSessionTestHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write(body)
 }
+
+func TestUserUnmarshalJSONTimestamps(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     string
+		expected User
+	}{
+		{
+			scenario: "valid timestamps are decoded",
+			data:     `{"id":"user_123","created_at":"2021-06-25T19:07:33.155Z","updated_at":"2021-06-26T19:07:33.155Z"}`,
+			expected: User{
+				ID:        "user_123",
+				CreatedAt: time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt: time.Date(2021, time.June, 26, 19, 7, 33, 155000000, time.UTC),
+			},
+		},
+		{
+			scenario: "empty timestamps are decoded as the zero time",
+			data:     `{"id":"user_123","created_at":"","updated_at":""}`,
+			expected: User{ID: "user_123"},
+		},
+		{
+			scenario: "null timestamps are decoded as the zero time",
+			data:     `{"id":"user_123","created_at":null,"updated_at":null}`,
+			expected: User{ID: "user_123"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var v User
+			err := json.Unmarshal([]byte(test.data), &v)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, v)
+		})
+	}
+}
+
+func TestUserLastSignInAt(t *testing.T) {
+	var user User
+	err := json.Unmarshal([]byte(`{"id":"user_123","last_sign_in_at":"2023-03-14T10:00:00.000Z"}`), &user)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2023, time.March, 14, 10, 0, 0, 0, time.UTC), user.LastSignInAt)
+
+	user = User{}
+	err = json.Unmarshal([]byte(`{"id":"user_123","last_sign_in_at":null}`), &user)
+	require.NoError(t, err)
+	require.True(t, user.LastSignInAt.IsZero())
+}
+
+func TestFilterInactive(t *testing.T) {
+	before := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	users := []User{
+		{ID: "user_never"},
+		{ID: "user_old", LastSignInAt: before.Add(-time.Second)},
+		{ID: "user_boundary", LastSignInAt: before},
+		{ID: "user_recent", LastSignInAt: before.Add(time.Second)},
+	}
+
+	inactive := FilterInactive(users, before)
+	require.Equal(t, []User{users[0], users[1]}, inactive)
+
+	require.Empty(t, FilterInactive(nil, before))
+}
+
+func TestDeleteEmptyResponses(t *testing.T) {
+	for _, status := range []int{http.StatusNoContent, http.StatusOK} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+		t.Run(fmt.Sprintf("DeleteUser succeeds with an empty %d", status), func(t *testing.T) {
+			err := client.DeleteUser(context.Background(), DeleteUserOpts{User: "user_123"})
+			require.NoError(t, err)
+		})
+
+		t.Run(fmt.Sprintf("DeleteOrganizationMembership succeeds with an empty %d", status), func(t *testing.T) {
+			err := client.DeleteOrganizationMembership(context.Background(), DeleteOrganizationMembershipOpts{
+				OrganizationMembership: "om_123",
+			})
+			require.NoError(t, err)
+		})
+
+		t.Run(fmt.Sprintf("RevokeSession succeeds with an empty %d", status), func(t *testing.T) {
+			err := client.RevokeSession(context.Background(), RevokeSessionOpts{SessionID: "session_123"})
+			require.NoError(t, err)
+		})
+
+		server.Close()
+	}
+}
+
+func TestUserFullName(t *testing.T) {
+	require.Equal(t, "marcelina", User{Email: "marcelina@foo-corp.com"}.FullName())
+	require.Equal(t, "Marcelina", User{FirstName: "Marcelina", Email: "marcelina@foo-corp.com"}.FullName())
+	require.Equal(t, "Marcelina Davis", User{FirstName: "Marcelina", LastName: "Davis", Email: "marcelina@foo-corp.com"}.FullName())
+}
+
+func TestUserEmailDomain(t *testing.T) {
+	require.Equal(t, "foo-corp.com", User{Email: "Marcelina+sso@Foo-Corp.com"}.EmailDomain())
+	require.Equal(t, "eu.foo-corp.com", User{Email: "marcelina@eu.foo-corp.com"}.EmailDomain())
+	require.Empty(t, User{Email: "marcelina@"}.EmailDomain())
+}
+
+func TestClientClose(t *testing.T) {
+	var closed int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(getUserTestHandler))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateClosed {
+			atomic.StoreInt32(&closed, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	t.Run("idle connections of the default transport are closed", func(t *testing.T) {
+		atomic.StoreInt32(&closed, 0)
+		client := NewClient("test", WithEndpoint(server.URL))
+
+		_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+		require.NoError(t, err)
+		require.Zero(t, atomic.LoadInt32(&closed))
+
+		client.Close()
+		require.Eventually(t, func() bool {
+			return atomic.LoadInt32(&closed) == 1
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("user-supplied clients are left untouched", func(t *testing.T) {
+		httpClient := server.Client()
+		client := NewClient("test", WithEndpoint(server.URL), WithHTTPClient(httpClient))
+
+		_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+		require.NoError(t, err)
+
+		atomic.StoreInt32(&closed, 0)
+		client.Close()
+		time.Sleep(20 * time.Millisecond)
+		require.Zero(t, atomic.LoadInt32(&closed))
+		httpClient.CloseIdleConnections()
+	})
+}
+
+func TestRequestEditors(t *testing.T) {
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Proxy-Authorization")
+		getUserTestHandler(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		"test",
+		WithHTTPClient(server.Client()),
+		WithEndpoint(server.URL),
+		WithRequestEditor(func(r *http.Request) error {
+			r.Header.Set("X-Proxy-Authorization", "proxy_token")
+			return nil
+		}),
+	)
+
+	_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+	require.NoError(t, err)
+	require.Equal(t, "proxy_token", header)
+}
+
+func TestClientConcurrentInit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(getUserTestHandler))
+	defer server.Close()
+
+	client := &Client{
+		APIKey:   "test",
+		Endpoint: server.URL,
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+	require.Equal(t, 10*time.Second, client.HTTPClient.Timeout)
+	client.Close()
+}
+
+func TestAuthenticateResponseUnmarshalJSON(t *testing.T) {
+	data := `{
+		"user": {
+			"id": "user_01E3JC5F5Z1YJNPGVYWV9SX6GH",
+			"email": "marcelina@foo-corp.com",
+			"first_name": "Marcelina",
+			"last_name": "Davis",
+			"email_verified": true,
+			"created_at": "2021-06-25T19:07:33.155Z",
+			"updated_at": "2021-06-25T19:07:33.155Z"
+		},
+		"organization_id": "org_01E4ZCR3C56J083X43JQXF3JK5",
+		"access_token": "eyJhb.nNzb19vaWRjX2tleS.lc5Uk4yWVk5In0",
+		"refresh_token": "yAjhKk123NLIjdrBdGZPf8pLIDvK",
+		"impersonator": {
+			"email": "admin@foocorp.com",
+			"reason": "Investigating an issue with the customer's account."
+		},
+		"authentication_method": "SSO"
+	}`
+
+	var res AuthenticateResponse
+	require.NoError(t, json.Unmarshal([]byte(data), &res))
+
+	createdAt := time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC)
+	require.Equal(t, AuthenticateResponse{
+		User: User{
+			ID:            "user_01E3JC5F5Z1YJNPGVYWV9SX6GH",
+			Email:         "marcelina@foo-corp.com",
+			FirstName:     "Marcelina",
+			LastName:      "Davis",
+			EmailVerified: true,
+			CreatedAt:     createdAt,
+			UpdatedAt:     createdAt,
+		},
+		OrganizationID: "org_01E4ZCR3C56J083X43JQXF3JK5",
+		AccessToken:    "eyJhb.nNzb19vaWRjX2tleS.lc5Uk4yWVk5In0",
+		RefreshToken:   "yAjhKk123NLIjdrBdGZPf8pLIDvK",
+		Impersonator: &Impersonator{
+			Email:  "admin@foocorp.com",
+			Reason: "Investigating an issue with the customer's account.",
+		},
+		AuthenticationMethod: SSO,
+	}, res)
+
+	res = AuthenticateResponse{}
+	require.NoError(t, json.Unmarshal([]byte(`{"user":{"id":"user_123"},"organization_id":null,"impersonator":null}`), &res))
+	require.Empty(t, res.OrganizationID)
+	require.Nil(t, res.Impersonator)
+}
+
+func TestAuthenticateResponseAuthenticationMethod(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     string
+		expected AuthenticationMethod
+	}{
+		{
+			scenario: "password",
+			data:     `{"access_token":"token","authentication_method":"Password"}`,
+			expected: Password,
+		},
+		{
+			scenario: "OAuth",
+			data:     `{"access_token":"token","authentication_method":"GoogleOAuth"}`,
+			expected: GoogleOAuth,
+		},
+		{
+			scenario: "Magic Auth",
+			data:     `{"access_token":"token","authentication_method":"MagicAuth"}`,
+			expected: MagicAuth,
+		},
+		{
+			scenario: "SSO",
+			data:     `{"access_token":"token","authentication_method":"SSO"}`,
+			expected: SSO,
+		},
+		{
+			scenario: "unknown method",
+			data:     `{"access_token":"token","authentication_method":"CarrierPigeon"}`,
+			expected: AuthenticationMethod("CarrierPigeon"),
+		},
+		{
+			scenario: "missing method",
+			data:     `{"access_token":"token"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var res AuthenticateResponse
+			err := json.Unmarshal([]byte(test.data), &res)
+			require.NoError(t, err)
+			require.Equal(t, "token", res.AccessToken)
+			require.Equal(t, test.expected, res.AuthenticationMethod)
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		getUserTestHandler(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient("test", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+	require.NoError(t, err)
+	require.Equal(t, "workos-go/"+common.Version, userAgent)
+
+	common.SetAppInfo("foo-corp", "1.2.3")
+	defer common.SetAppInfo("", "")
+
+	_, err = client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(userAgent, "workos-go/"+common.Version+" "))
+	require.True(t, strings.HasSuffix(userAgent, " foo-corp/1.2.3"))
+}
+
+func TestClientMissingAPIKey(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	client := NewClient("", WithHTTPClient(server.Client()), WithEndpoint(server.URL))
+
+	_, err := client.GetUser(context.Background(), GetUserOpts{User: "user_123"})
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+
+	_, err = client.ListUsers(context.Background(), ListUsersOpts{})
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+
+	_, err = client.AuthenticateWithPassword(context.Background(), AuthenticateWithPasswordOpts{
+		ClientID: "client_123",
+		Email:    "marcelina@foo-corp.com",
+		Password: "password",
+	})
+	require.Equal(t, workos_errors.ErrMissingAPIKey, err)
+
+	require.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestListUsersOptsPages(t *testing.T) {
+	opts := ListUsersOpts{Email: "marcelina@foo-corp.com", Limit: 5, Order: Asc, After: "user_1"}
+
+	next := opts.NextPage(common.ListMetadata{Before: "user_2", After: "user_6"})
+	expected := opts
+	expected.After = "user_6"
+	require.Equal(t, expected, next)
+
+	prev := next.PrevPage(common.ListMetadata{Before: "user_7", After: "user_11"})
+	expected.Before = "user_7"
+	expected.After = ""
+	require.Equal(t, expected, prev)
+}
@@ -0,0 +1,10 @@
+package usermanagement
+
+import "github.com/workos/workos-go/v4/internal/workos"
+
+// Limits of the User metadata enforced by the WorkOS API.
+const (
+	MaxMetadataKeys        = workos.MaxMetadataKeys
+	MaxMetadataKeyLength   = workos.MaxMetadataKeyLength
+	MaxMetadataValueLength = workos.MaxMetadataValueLength
+)
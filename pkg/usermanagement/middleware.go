@@ -0,0 +1,65 @@
+package usermanagement
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type sessionContextKey struct{}
+
+// SessionMiddlewareOpts contains the options to protect handlers with
+// SessionMiddleware.
+type SessionMiddlewareOpts struct {
+	// The options used to load, refresh and reseal the session.
+	LoadSessionOpts
+
+	// Handles the requests without a valid session. The session cookie has
+	// already been cleared when err is ErrNeedsReauth or ErrInvalidSealed.
+	// Defaults to responding with 401 Unauthorized.
+	//
+	// OPTIONAL.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// SessionMiddleware returns a middleware that loads the session of each
+// request with LoadSessionFromRequest before calling the next handler.
+//
+// A refreshed session is resealed and written back in the response cookie.
+// The session is attached to the request context and can be read with
+// SessionFromContext. When the session is invalid or could not be refreshed,
+// the cookie is cleared and opts.OnError is called instead of the next handler.
+func (c *Client) SessionMiddleware(opts SessionMiddlewareOpts) func(http.Handler) http.Handler {
+	onError := opts.OnError
+	if onError == nil {
+		onError = func(w http.ResponseWriter, r *http.Request, err error) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			res, err := c.LoadSessionFromRequest(r, opts.LoadSessionOpts)
+			if err != nil {
+				if errors.Is(err, ErrNeedsReauth) || errors.Is(err, ErrInvalidSealed) {
+					http.SetCookie(w, newClearSessionCookie(opts.Cookie))
+				}
+				onError(w, r, err)
+				return
+			}
+
+			if res.Cookie != nil {
+				http.SetCookie(w, res.Cookie)
+			}
+
+			ctx := context.WithValue(r.Context(), sessionContextKey{}, res.Session)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// SessionFromContext returns the session attached to ctx by SessionMiddleware.
+func SessionFromContext(ctx context.Context) (Session, bool) {
+	session, ok := ctx.Value(sessionContextKey{}).(Session)
+	return session, ok
+}
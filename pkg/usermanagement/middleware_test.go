@@ -0,0 +1,125 @@
+package usermanagement
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionMiddleware(t *testing.T) {
+	tests := []struct {
+		scenario      string
+		accessToken   string
+		refreshStatus int
+		noCookie      bool
+		status        int
+		refreshed     bool
+		cleared       bool
+	}{
+		{
+			scenario:    "valid session reaches the handler",
+			accessToken: testAccessToken(time.Now().Add(time.Hour)),
+			status:      http.StatusOK,
+		},
+		{
+			scenario:      "session near expiry is refreshed and resealed",
+			accessToken:   testAccessToken(time.Now().Add(time.Minute)),
+			refreshStatus: http.StatusOK,
+			status:        http.StatusOK,
+			refreshed:     true,
+		},
+		{
+			scenario:      "refresh failure clears the cookie",
+			accessToken:   testAccessToken(time.Now().Add(-time.Minute)),
+			refreshStatus: http.StatusBadRequest,
+			status:        http.StatusUnauthorized,
+			cleared:       true,
+		},
+		{
+			scenario: "missing cookie is unauthorized",
+			noCookie: true,
+			status:   http.StatusUnauthorized,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			refreshedToken := testAccessToken(time.Now().Add(time.Hour))
+
+			workosServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.refreshStatus != http.StatusOK {
+					http.Error(w, "invalid refresh token", http.StatusBadRequest)
+					return
+				}
+				json.NewEncoder(w).Encode(RefreshAuthenticationResponse{
+					AccessToken:  refreshedToken,
+					RefreshToken: "new_refresh_token",
+				})
+			}))
+			defer workosServer.Close()
+
+			client := NewClient("test", WithHTTPClient(workosServer.Client()), WithEndpoint(workosServer.URL))
+
+			cookieOpts := SessionCookieOpts{Password: testSessionPassword, Insecure: true}
+			middleware := client.SessionMiddleware(SessionMiddlewareOpts{
+				LoadSessionOpts: LoadSessionOpts{
+					ClientID:      "client_123",
+					Cookie:        cookieOpts,
+					RefreshBefore: 5 * time.Minute,
+				},
+			})
+
+			app := httptest.NewServer(middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				session, ok := SessionFromContext(r.Context())
+				require.True(t, ok)
+				w.Write([]byte(session.User.ID))
+			})))
+			defer app.Close()
+
+			req, err := http.NewRequest(http.MethodGet, app.URL, nil)
+			require.NoError(t, err)
+			if !test.noCookie {
+				cookie, err := NewSessionCookie(AuthenticateResponse{
+					User:         User{ID: "user_123"},
+					AccessToken:  test.accessToken,
+					RefreshToken: "refresh_token",
+				}, cookieOpts)
+				require.NoError(t, err)
+				req.AddCookie(cookie)
+			}
+
+			res, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			require.Equal(t, test.status, res.StatusCode)
+
+			cookies := res.Cookies()
+			switch {
+			case test.refreshed:
+				require.Len(t, cookies, 1)
+				session, err := UnsealSession(cookies[0].Value, testSessionPassword)
+				require.NoError(t, err)
+				require.Equal(t, refreshedToken, session.AccessToken)
+				require.Equal(t, "new_refresh_token", session.RefreshToken)
+			case test.cleared:
+				require.Len(t, cookies, 1)
+				require.Equal(t, SessionCookieName, cookies[0].Name)
+				require.Empty(t, cookies[0].Value)
+				require.True(t, cookies[0].MaxAge < 0)
+			default:
+				require.Empty(t, cookies)
+			}
+
+			if test.status == http.StatusOK {
+				body, err := ioutil.ReadAll(res.Body)
+				require.NoError(t, err)
+				require.Equal(t, "user_123", string(body))
+			}
+		})
+	}
+}
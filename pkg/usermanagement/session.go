@@ -0,0 +1,327 @@
+package usermanagement
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SessionCookieName is the name of the cookie holding a sealed session.
+const SessionCookieName = "wos-session"
+
+// MinSessionPasswordLength is the minimum length of the password sealing
+// sessions, so that sealed sessions cannot be brute-forced offline.
+const MinSessionPasswordLength = 32
+
+// This represents the list of errors that could be raised when loading a
+// session.
+var (
+	ErrNoSession     = errors.New("no session cookie")
+	ErrNeedsReauth   = errors.New("session expired and could not be refreshed")
+	ErrInvalidSealed = errors.New("sealed session is invalid")
+)
+
+// Session contains the data sealed into a session cookie.
+type Session struct {
+	// The authenticated User.
+	User User `json:"user"`
+
+	// The Organization the user signed in to. Can be empty.
+	OrganizationID string `json:"organization_id,omitempty"`
+
+	// The AccessToken of the session.
+	AccessToken string `json:"access_token"`
+
+	// The RefreshToken used to obtain a new AccessToken.
+	RefreshToken string `json:"refresh_token"`
+
+	// Present if the authenticated user is being impersonated.
+	Impersonator *Impersonator `json:"impersonator,omitempty"`
+
+	// How the user authenticated.
+	AuthenticationMethod AuthenticationMethod `json:"authentication_method,omitempty"`
+
+	// When the user authenticated. It is kept when the session is refreshed.
+	AuthenticatedAt time.Time `json:"authenticated_at"`
+}
+
+// SessionCookieOpts contains the options to build a session cookie.
+type SessionCookieOpts struct {
+	// The password used to seal the session. It must be a random string of
+	// at least MinSessionPasswordLength characters.
+	//
+	// REQUIRED.
+	Password string
+
+	// The name of the cookie. Defaults to SessionCookieName.
+	Name string
+
+	// The Domain attribute of the cookie, e.g. ".example.com" to share the
+	// session across subdomains. Defaults to the host of the request.
+	Domain string
+
+	// The Path attribute of the cookie. Defaults to "/".
+	Path string
+
+	// The SameSite attribute of the cookie. Defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// The MaxAge attribute of the cookie, in seconds. Zero makes it a session
+	// cookie and a negative value deletes it.
+	MaxAge int
+
+	// When true, the cookie is not marked Secure so it is also sent over plain
+	// HTTP, e.g. during local development.
+	Insecure bool
+}
+
+func (opts SessionCookieOpts) name() string {
+	if opts.Name == "" {
+		return SessionCookieName
+	}
+	return opts.Name
+}
+
+// NewSessionCookie seals the session described by the given
+// AuthenticateResponse and returns a HttpOnly cookie containing it, Secure
+// unless opts.Insecure is set.
+func NewSessionCookie(res AuthenticateResponse, opts SessionCookieOpts) (*http.Cookie, error) {
+	return newSessionCookie(Session{
+		User:                 res.User,
+		OrganizationID:       res.OrganizationID,
+		AccessToken:          res.AccessToken,
+		RefreshToken:         res.RefreshToken,
+		Impersonator:         res.Impersonator,
+		AuthenticationMethod: res.AuthenticationMethod,
+		AuthenticatedAt:      time.Now().UTC(),
+	}, opts)
+}
+
+func newSessionCookie(session Session, opts SessionCookieOpts) (*http.Cookie, error) {
+	sealed, err := SealSession(session, opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.cookie(sealed, opts.MaxAge), nil
+}
+
+// newClearSessionCookie returns a cookie that deletes the session cookie
+// described by opts.
+func newClearSessionCookie(opts SessionCookieOpts) *http.Cookie {
+	return opts.cookie("", -1)
+}
+
+func (opts SessionCookieOpts) cookie(value string, maxAge int) *http.Cookie {
+	sameSite := opts.SameSite
+	if sameSite == 0 {
+		sameSite = http.SameSiteLaxMode
+	}
+
+	path := opts.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return &http.Cookie{
+		Name:     opts.name(),
+		Value:    value,
+		Domain:   opts.Domain,
+		Path:     path,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   !opts.Insecure,
+		SameSite: sameSite,
+	}
+}
+
+// SealSession encrypts the given session with a key derived from password,
+// which must be at least MinSessionPasswordLength characters long.
+func SealSession(session Session, password string) (string, error) {
+	if password == "" {
+		return "", errors.New("incomplete arguments: missing Password")
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := sessionCipher(password)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, data, nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// UnsealSession decrypts a session sealed with SealSession.
+func UnsealSession(sealed, password string) (Session, error) {
+	if password == "" {
+		return Session{}, errors.New("incomplete arguments: missing Password")
+	}
+
+	gcm, err := sessionCipher(password)
+	if err != nil {
+		return Session{}, err
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil {
+		return Session{}, ErrInvalidSealed
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return Session{}, ErrInvalidSealed
+	}
+
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return Session{}, ErrInvalidSealed
+	}
+
+	var session Session
+	if err = json.Unmarshal(plaintext, &session); err != nil {
+		return Session{}, ErrInvalidSealed
+	}
+
+	return session, nil
+}
+
+func sessionCipher(password string) (cipher.AEAD, error) {
+	if len(password) < MinSessionPasswordLength {
+		return nil, fmt.Errorf("invalid Password: must be at least %d characters", MinSessionPasswordLength)
+	}
+
+	key := sha256.Sum256([]byte(password))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// LoadSessionOpts contains the options to load a session from a request.
+type LoadSessionOpts struct {
+	// Your WorkOS Project's Client ID. Used to refresh expired sessions.
+	//
+	// REQUIRED.
+	ClientID string
+
+	// The options used to unseal the session and to build the refreshed
+	// session cookie.
+	//
+	// REQUIRED.
+	Cookie SessionCookieOpts
+
+	// How long before its expiry the access token is refreshed. Defaults to
+	// refreshing only once the access token has expired.
+	//
+	// OPTIONAL.
+	RefreshBefore time.Duration
+}
+
+// LoadSessionResponse contains the response from the LoadSessionFromRequest
+// call.
+type LoadSessionResponse struct {
+	// The unsealed, valid session.
+	Session Session
+
+	// The refreshed session cookie. It is only set when the access token had
+	// expired and was refreshed, in which case it must be written back to the
+	// response.
+	Cookie *http.Cookie
+}
+
+// LoadSessionFromRequest unseals the session cookie of the given request and
+// validates it, refreshing the access token when it has expired or expires
+// within opts.RefreshBefore.
+//
+// It returns ErrNoSession when the request has no session cookie and
+// ErrNeedsReauth when the session expired and could not be refreshed.
+func (c *Client) LoadSessionFromRequest(r *http.Request, opts LoadSessionOpts) (LoadSessionResponse, error) {
+	cookie, err := r.Cookie(opts.Cookie.name())
+	if err != nil || cookie.Value == "" {
+		return LoadSessionResponse{}, ErrNoSession
+	}
+
+	session, err := UnsealSession(cookie.Value, opts.Cookie.Password)
+	if err != nil {
+		return LoadSessionResponse{}, err
+	}
+
+	if !accessTokenExpired(session.AccessToken, time.Now().Add(opts.RefreshBefore)) {
+		return LoadSessionResponse{Session: session}, nil
+	}
+
+	if session.RefreshToken == "" {
+		return LoadSessionResponse{}, ErrNeedsReauth
+	}
+
+	refreshed, err := c.AuthenticateWithRefreshToken(r.Context(), AuthenticateWithRefreshTokenOpts{
+		ClientID:     opts.ClientID,
+		RefreshToken: session.RefreshToken,
+	})
+	if err != nil {
+		return LoadSessionResponse{}, fmt.Errorf("%w: %v", ErrNeedsReauth, err)
+	}
+
+	session.AccessToken = refreshed.AccessToken
+	session.RefreshToken = refreshed.RefreshToken
+
+	newCookie, err := newSessionCookie(session, opts.Cookie)
+	if err != nil {
+		return LoadSessionResponse{}, err
+	}
+
+	return LoadSessionResponse{Session: session, Cookie: newCookie}, nil
+}
+
+// accessTokenClaims contains the access token claims read by the session
+// helpers. The token itself is trusted because it comes from a sealed session.
+type accessTokenClaims struct {
+	ExpiresAt int64 `json:"exp"`
+}
+
+func parseAccessTokenClaims(token string) (accessTokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return accessTokenClaims{}, errors.New("malformed access token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return accessTokenClaims{}, err
+	}
+
+	var claims accessTokenClaims
+	err = json.Unmarshal(payload, &claims)
+	return claims, err
+}
+
+func accessTokenExpired(token string, now time.Time) bool {
+	claims, err := parseAccessTokenClaims(token)
+	if err != nil {
+		return true
+	}
+
+	return claims.ExpiresAt == 0 || !now.Before(time.Unix(claims.ExpiresAt, 0))
+}
@@ -0,0 +1,273 @@
+package usermanagement
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSessionPassword = "kR620keEzOIzPThfnMEAba8XYgKdQ5vg"
+
+func testAccessToken(expiresAt time.Time) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sid":"session_123","exp":%d}`, expiresAt.Unix())))
+	return header + "." + payload + ".signature"
+}
+
+func TestSealSession(t *testing.T) {
+	session := Session{
+		User:         User{ID: "user_123", Email: "marcelina@foo-corp.com"},
+		AccessToken:  "access_token",
+		RefreshToken: "refresh_token",
+	}
+
+	sealed, err := SealSession(session, testSessionPassword)
+	require.NoError(t, err)
+	require.NotContains(t, sealed, "access_token")
+
+	unsealed, err := UnsealSession(sealed, testSessionPassword)
+	require.NoError(t, err)
+	require.Equal(t, session, unsealed)
+
+	_, err = UnsealSession(sealed, "qBbYHZ6dHm0ERVpJ2I5AcKshc3HJDnIc")
+	require.Equal(t, ErrInvalidSealed, err)
+
+	_, err = SealSession(session, "")
+	require.Error(t, err)
+
+	_, err = SealSession(session, "short password")
+	require.EqualError(t, err, "invalid Password: must be at least 32 characters")
+
+	_, err = UnsealSession(sealed, "short password")
+	require.EqualError(t, err, "invalid Password: must be at least 32 characters")
+}
+
+func TestNewSessionCookie(t *testing.T) {
+	cookie, err := NewSessionCookie(AuthenticateResponse{
+		User:                 User{ID: "user_123"},
+		AccessToken:          "access_token",
+		AuthenticationMethod: Passkey,
+	}, SessionCookieOpts{Password: testSessionPassword})
+	require.NoError(t, err)
+	require.Equal(t, SessionCookieName, cookie.Name)
+
+	session, err := UnsealSession(cookie.Value, testSessionPassword)
+	require.NoError(t, err)
+	require.Equal(t, Passkey, session.AuthenticationMethod)
+	require.WithinDuration(t, time.Now(), session.AuthenticatedAt, time.Minute)
+	require.True(t, cookie.HttpOnly)
+	require.True(t, cookie.Secure)
+	require.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+
+	cookie, err = NewSessionCookie(AuthenticateResponse{}, SessionCookieOpts{
+		Password: testSessionPassword,
+		SameSite: http.SameSiteStrictMode,
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+}
+
+func TestNewSessionCookieOptions(t *testing.T) {
+	t.Run("defaults are secure", func(t *testing.T) {
+		cookie, err := NewSessionCookie(AuthenticateResponse{}, SessionCookieOpts{Password: testSessionPassword})
+		require.NoError(t, err)
+		require.Equal(t, SessionCookieName, cookie.Name)
+		require.Equal(t, "/", cookie.Path)
+		require.Empty(t, cookie.Domain)
+		require.Zero(t, cookie.MaxAge)
+		require.True(t, cookie.HttpOnly)
+		require.True(t, cookie.Secure)
+		require.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+	})
+
+	t.Run("custom domain and SameSite", func(t *testing.T) {
+		cookieOpts := SessionCookieOpts{
+			Password: testSessionPassword,
+			Name:     "session",
+			Domain:   ".example.com",
+			Path:     "/app",
+			SameSite: http.SameSiteNoneMode,
+			MaxAge:   3600,
+		}
+
+		cookie, err := NewSessionCookie(AuthenticateResponse{
+			User:        User{ID: "user_123"},
+			AccessToken: testAccessToken(time.Now().Add(time.Hour)),
+		}, cookieOpts)
+		require.NoError(t, err)
+		require.Equal(t, "session", cookie.Name)
+		require.Equal(t, ".example.com", cookie.Domain)
+		require.Equal(t, "/app", cookie.Path)
+		require.Equal(t, 3600, cookie.MaxAge)
+		require.True(t, cookie.HttpOnly)
+		require.True(t, cookie.Secure)
+		require.Equal(t, http.SameSiteNoneMode, cookie.SameSite)
+		require.Contains(t, cookie.String(), "Domain=example.com")
+
+		r := httptest.NewRequest(http.MethodGet, "/app", nil)
+		r.AddCookie(cookie)
+
+		client := NewClient("test")
+		res, err := client.LoadSessionFromRequest(r, LoadSessionOpts{Cookie: cookieOpts})
+		require.NoError(t, err)
+		require.Equal(t, "user_123", res.Session.User.ID)
+	})
+
+	t.Run("insecure cookie", func(t *testing.T) {
+		cookie, err := NewSessionCookie(AuthenticateResponse{}, SessionCookieOpts{
+			Password: testSessionPassword,
+			Insecure: true,
+		})
+		require.NoError(t, err)
+		require.False(t, cookie.Secure)
+		require.True(t, cookie.HttpOnly)
+	})
+}
+
+func TestLoginSessionRoundtrip(t *testing.T) {
+	tests := []struct {
+		scenario        string
+		accessToken     string
+		refreshToken    string
+		refreshStatus   int
+		noCookie        bool
+		err             error
+		expectRefreshed bool
+	}{
+		{
+			scenario:     "valid session is loaded",
+			accessToken:  testAccessToken(time.Now().Add(time.Hour)),
+			refreshToken: "refresh_token",
+		},
+		{
+			scenario:        "expired session is refreshed",
+			accessToken:     testAccessToken(time.Now().Add(-time.Minute)),
+			refreshToken:    "refresh_token",
+			refreshStatus:   http.StatusOK,
+			expectRefreshed: true,
+		},
+		{
+			scenario:      "expired session that cannot be refreshed needs reauth",
+			accessToken:   testAccessToken(time.Now().Add(-time.Minute)),
+			refreshToken:  "refresh_token",
+			refreshStatus: http.StatusBadRequest,
+			err:           ErrNeedsReauth,
+		},
+		{
+			scenario:    "expired session without refresh token needs reauth",
+			accessToken: testAccessToken(time.Now().Add(-time.Minute)),
+			err:         ErrNeedsReauth,
+		},
+		{
+			scenario: "missing cookie returns no session",
+			noCookie: true,
+			err:      ErrNoSession,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			refreshedToken := testAccessToken(time.Now().Add(time.Hour))
+
+			workosServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var payload map[string]interface{}
+				json.NewDecoder(r.Body).Decode(&payload)
+
+				switch payload["grant_type"] {
+				case "password":
+					json.NewEncoder(w).Encode(AuthenticateResponse{
+						User:         User{ID: "user_123", Email: "marcelina@foo-corp.com"},
+						AccessToken:  test.accessToken,
+						RefreshToken: test.refreshToken,
+					})
+				case "refresh_token":
+					if test.refreshStatus != http.StatusOK {
+						http.Error(w, "invalid refresh token", test.refreshStatus)
+						return
+					}
+					json.NewEncoder(w).Encode(RefreshAuthenticationResponse{
+						AccessToken:  refreshedToken,
+						RefreshToken: "new_refresh_token",
+					})
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+				}
+			}))
+			defer workosServer.Close()
+
+			client := NewClient("test")
+			client.Endpoint = workosServer.URL
+			client.HTTPClient = workosServer.Client()
+
+			cookieOpts := SessionCookieOpts{Password: testSessionPassword}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+				res, err := client.AuthenticateWithPassword(r.Context(), AuthenticateWithPasswordOpts{
+					ClientID: "client_123",
+					Email:    "marcelina@foo-corp.com",
+					Password: "password",
+				})
+				require.NoError(t, err)
+
+				cookie, err := NewSessionCookie(res, cookieOpts)
+				require.NoError(t, err)
+				http.SetCookie(w, cookie)
+			})
+
+			var loaded LoadSessionResponse
+			var loadErr error
+			mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+				loaded, loadErr = client.LoadSessionFromRequest(r, LoadSessionOpts{
+					ClientID: "client_123",
+					Cookie:   cookieOpts,
+				})
+			})
+
+			app := httptest.NewServer(mux)
+			defer app.Close()
+
+			res, err := http.Get(app.URL + "/login")
+			require.NoError(t, err)
+			res.Body.Close()
+			cookies := res.Cookies()
+			require.Len(t, cookies, 1)
+
+			req, err := http.NewRequest(http.MethodGet, app.URL+"/me", nil)
+			require.NoError(t, err)
+			if !test.noCookie {
+				req.AddCookie(cookies[0])
+			}
+			res, err = http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			res.Body.Close()
+
+			if test.err != nil {
+				require.True(t, errors.Is(loadErr, test.err))
+				return
+			}
+			require.NoError(t, loadErr)
+			require.Equal(t, "user_123", loaded.Session.User.ID)
+
+			if test.expectRefreshed {
+				require.Equal(t, refreshedToken, loaded.Session.AccessToken)
+				require.Equal(t, "new_refresh_token", loaded.Session.RefreshToken)
+				require.NotNil(t, loaded.Cookie)
+
+				session, err := UnsealSession(loaded.Cookie.Value, testSessionPassword)
+				require.NoError(t, err)
+				require.Equal(t, refreshedToken, session.AccessToken)
+			} else {
+				require.Equal(t, test.accessToken, loaded.Session.AccessToken)
+				require.Nil(t, loaded.Cookie)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package usermanagement
+
+import (
+	"net/url"
+	"time"
+)
+
+// StepUpPolicy describes how a user must have authenticated to perform an
+// action, e.g. an admin action.
+//
+// A policy cannot require MFA: the AuthenticationMethod of a session only
+// reports the primary method, so MFA completion is not recorded. Require a
+// recent authentication with MaxAge instead, and enforce MFA on the
+// Organization so that authenticating again includes it.
+type StepUpPolicy struct {
+	// The methods the user must have authenticated with. Any method is
+	// accepted when empty.
+	Methods []AuthenticationMethod
+
+	// How long ago the user may have authenticated at most. The authentication
+	// never expires when zero.
+	MaxAge time.Duration
+}
+
+// RequiresStepUp reports whether the user of the given session must
+// authenticate again to satisfy the given policy. Sessions with an unknown
+// authentication time never satisfy a MaxAge.
+func RequiresStepUp(session Session, policy StepUpPolicy) bool {
+	if policy.MaxAge > 0 && (session.AuthenticatedAt.IsZero() || time.Since(session.AuthenticatedAt) > policy.MaxAge) {
+		return true
+	}
+
+	if len(policy.Methods) == 0 {
+		return false
+	}
+
+	for _, method := range policy.Methods {
+		if session.AuthenticationMethod == method {
+			return false
+		}
+	}
+	return true
+}
+
+// GetStepUpURLOpts contains the options to generate a step-up authorization
+// URL.
+type GetStepUpURLOpts struct {
+	// The options of the authorization URL. ClientID and RedirectURI are
+	// REQUIRED.
+	GetAuthorizationURLOpts
+
+	// The session of the user who must authenticate again.
+	Session Session
+}
+
+// GetStepUpURL generates an authorization URL for the user of the given
+// session to authenticate again, typically after RequiresStepUp returned true.
+//
+// The login hint defaults to the email of the User, and the connection
+// selector to the Organization of the session, or to AuthKit when the session
+// has none.
+func (c *Client) GetStepUpURL(opts GetStepUpURLOpts) (*url.URL, error) {
+	authOpts := opts.GetAuthorizationURLOpts
+	if authOpts.LoginHint == "" {
+		authOpts.LoginHint = opts.Session.User.Email
+	}
+
+	if authOpts.Provider == "" && authOpts.ConnectionID == "" && authOpts.OrganizationID == "" {
+		if opts.Session.OrganizationID != "" {
+			authOpts.OrganizationID = opts.Session.OrganizationID
+		} else {
+			authOpts.Provider = "authkit"
+		}
+	}
+
+	return c.GetAuthorizationURL(authOpts)
+}
@@ -0,0 +1,104 @@
+package usermanagement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiresStepUp(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		scenario        string
+		method          AuthenticationMethod
+		authenticatedAt time.Time
+		policy          StepUpPolicy
+		expected        bool
+	}{
+		{
+			scenario: "no policy",
+			method:   MagicAuth,
+		},
+		{
+			scenario: "method satisfies the policy",
+			method:   SSO,
+			policy:   StepUpPolicy{Methods: []AuthenticationMethod{SSO, Passkey}},
+		},
+		{
+			scenario: "method does not satisfy the policy",
+			method:   MagicAuth,
+			policy:   StepUpPolicy{Methods: []AuthenticationMethod{SSO, Passkey}},
+			expected: true,
+		},
+		{
+			scenario: "unknown method does not satisfy the policy",
+			policy:   StepUpPolicy{Methods: []AuthenticationMethod{Password}},
+			expected: true,
+		},
+		{
+			scenario:        "recent authentication satisfies the policy",
+			method:          Password,
+			authenticatedAt: now.Add(-time.Minute),
+			policy:          StepUpPolicy{Methods: []AuthenticationMethod{Password}, MaxAge: 5 * time.Minute},
+		},
+		{
+			scenario:        "stale authentication does not satisfy the policy",
+			method:          Password,
+			authenticatedAt: now.Add(-time.Hour),
+			policy:          StepUpPolicy{Methods: []AuthenticationMethod{Password}, MaxAge: 5 * time.Minute},
+			expected:        true,
+		},
+		{
+			scenario: "unknown authentication time does not satisfy the policy",
+			method:   Password,
+			policy:   StepUpPolicy{MaxAge: 5 * time.Minute},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			session := Session{
+				AuthenticationMethod: test.method,
+				AuthenticatedAt:      test.authenticatedAt,
+			}
+			require.Equal(t, test.expected, RequiresStepUp(session, test.policy))
+		})
+	}
+}
+
+func TestGetStepUpURL(t *testing.T) {
+	client := NewClient("test")
+
+	session := Session{
+		User:           User{ID: "user_123", Email: "marcelina@foo-corp.com"},
+		OrganizationID: "org_123",
+	}
+
+	u, err := client.GetStepUpURL(GetStepUpURLOpts{
+		GetAuthorizationURLOpts: GetAuthorizationURLOpts{
+			ClientID:    "client_123",
+			RedirectURI: "https://example.com/callback",
+		},
+		Session: session,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "marcelina@foo-corp.com", u.Query().Get("login_hint"))
+	require.Equal(t, "org_123", u.Query().Get("organization"))
+	require.Empty(t, u.Query().Get("provider"))
+
+	session.OrganizationID = ""
+	u, err = client.GetStepUpURL(GetStepUpURLOpts{
+		GetAuthorizationURLOpts: GetAuthorizationURLOpts{
+			ClientID:    "client_123",
+			RedirectURI: "https://example.com/callback",
+			LoginHint:   "marcelina@example.com",
+		},
+		Session: session,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "marcelina@example.com", u.Query().Get("login_hint"))
+	require.Equal(t, "authkit", u.Query().Get("provider"))
+}
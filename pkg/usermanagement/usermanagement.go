@@ -3,8 +3,13 @@ package usermanagement
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"net/url"
+	"sync"
+	"time"
+
+	"github.com/workos/workos-go/v4/pkg/common"
 )
 
 var (
@@ -25,6 +30,17 @@ type Client struct {
 	// Defaults to http.Client.
 	HTTPClient *http.Client
 
+	// The observer notified after every request sent to WorkOS. Optional.
+	Observer common.Observer
+
+	// The logger used to log every request sent to WorkOS, with its route,
+	// status and duration. Optional.
+	Logger *log.Logger
+
+	// The functions applied to every request before it is sent, e.g. to add
+	// the headers required by a proxy. An error aborts the request. Optional.
+	RequestEditors []func(*http.Request) error
+
 	// The endpoint to WorkOS API.
 	//
 	// Defaults to https://api.workos.com.
@@ -32,6 +48,13 @@ type Client struct {
 
 	// The function used to encode in JSON. Defaults to json.Marshal.
 	JSONEncode func(v interface{}) ([]byte, error)
+
+	// When true, the emails of the returned Users are lowercased.
+	NormalizeEmail bool
+
+	timeout        time.Duration
+	ownedTransport http.RoundTripper
+	once           sync.Once
 }
 
 // SetAPIKey configures the default client that is used by the User management methods
@@ -56,6 +79,55 @@ func ListUsers(
 	return DefaultClient.ListUsers(ctx, opts)
 }
 
+// ForEachUser calls fn for each User matching the given criteria, with
+// bounded concurrency.
+func ForEachUser(
+	ctx context.Context,
+	opts ForEachUserOpts,
+	fn func(context.Context, User) error,
+) error {
+	return DefaultClient.ForEachUser(ctx, opts, fn)
+}
+
+// StreamUsers sends the Users matching the given criteria on the returned
+// channel.
+func StreamUsers(
+	ctx context.Context,
+	opts ListUsersOpts,
+) (<-chan User, <-chan error) {
+	return DefaultClient.StreamUsers(ctx, opts)
+}
+
+// Ping checks that WorkOS is reachable and that the API key is valid.
+func Ping(ctx context.Context) error {
+	return DefaultClient.Ping(ctx)
+}
+
+// GetUserByEmail looks up a User by email.
+func GetUserByEmail(
+	ctx context.Context,
+	opts GetUserByEmailOpts,
+) (User, bool, error) {
+	return DefaultClient.GetUserByEmail(ctx, opts)
+}
+
+// GetUserByExternalID looks up a User by external identifier.
+func GetUserByExternalID(
+	ctx context.Context,
+	opts GetUserByExternalIDOpts,
+) (User, bool, error) {
+	return DefaultClient.GetUserByExternalID(ctx, opts)
+}
+
+// GetUserBySSOProfile looks up the member of the Organization of an SSO
+// Profile whose email matches the email of the Profile.
+func GetUserBySSOProfile(
+	ctx context.Context,
+	opts GetUserBySSOProfileOpts,
+) (User, bool, error) {
+	return DefaultClient.GetUserBySSOProfile(ctx, opts)
+}
+
 // CreateUser creates a User.
 func CreateUser(
 	ctx context.Context,
@@ -86,6 +158,16 @@ func GetAuthorizationURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
 	return DefaultClient.GetAuthorizationURL(opts)
 }
 
+// GetSignUpURL returns an AuthKit authorization url opening the sign up screen.
+func GetSignUpURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
+	return DefaultClient.GetSignUpURL(opts)
+}
+
+// GetSignInURL returns an AuthKit authorization url opening the sign in screen.
+func GetSignInURL(opts GetAuthorizationURLOpts) (*url.URL, error) {
+	return DefaultClient.GetSignInURL(opts)
+}
+
 // AuthenticateWithPassword authenticates a user with email and password
 func AuthenticateWithPassword(
 	ctx context.Context,
@@ -224,6 +306,14 @@ func CreateOrganizationMembership(
 	return DefaultClient.CreateOrganizationMembership(ctx, opts)
 }
 
+// CreateOrganizationMemberships adds several Users to an Organization.
+func CreateOrganizationMemberships(
+	ctx context.Context,
+	opts CreateOrganizationMembershipsOpts,
+) []CreateOrganizationMembershipResult {
+	return DefaultClient.CreateOrganizationMemberships(ctx, opts)
+}
+
 // UpdateOrganizationMembership updates an OrganizationMembership.
 func UpdateOrganizationMembership(
 	ctx context.Context,
@@ -233,6 +323,22 @@ func UpdateOrganizationMembership(
 	return DefaultClient.UpdateOrganizationMembership(ctx, organizationMembershipId, opts)
 }
 
+// ActivateOrganizationMembership activates an OrganizationMembership.
+func ActivateOrganizationMembership(
+	ctx context.Context,
+	opts ActivateOrganizationMembershipOpts,
+) (OrganizationMembership, error) {
+	return DefaultClient.ActivateOrganizationMembership(ctx, opts)
+}
+
+// DeactivateOrganizationMembership deactivates an OrganizationMembership.
+func DeactivateOrganizationMembership(
+	ctx context.Context,
+	opts DeactivateOrganizationMembershipOpts,
+) (OrganizationMembership, error) {
+	return DefaultClient.DeactivateOrganizationMembership(ctx, opts)
+}
+
 // DeleteOrganizationMembership deletes an existing OrganizationMembership.
 func DeleteOrganizationMembership(
 	ctx context.Context,
@@ -241,6 +347,40 @@ func DeleteOrganizationMembership(
 	return DefaultClient.DeleteOrganizationMembership(ctx, opts)
 }
 
+// RemoveUserFromAllOrganizations deletes every Organization Membership of a
+// User.
+func RemoveUserFromAllOrganizations(
+	ctx context.Context,
+	opts RemoveUserFromAllOrganizationsOpts,
+) error {
+	return DefaultClient.RemoveUserFromAllOrganizations(ctx, opts)
+}
+
+// CreateUsers creates the given Users sequentially, optionally rolling back on
+// failure.
+func CreateUsers(
+	ctx context.Context,
+	opts CreateUsersOpts,
+) ([]User, error) {
+	return DefaultClient.CreateUsers(ctx, opts)
+}
+
+// UpdateUserPassword sets the password of a User.
+func UpdateUserPassword(
+	ctx context.Context,
+	opts UpdateUserPasswordOpts,
+) (User, error) {
+	return DefaultClient.UpdateUserPassword(ctx, opts)
+}
+
+// GetUserWithMemberships gets a User and all their Organization Memberships.
+func GetUserWithMemberships(
+	ctx context.Context,
+	opts GetUserOpts,
+) (UserWithMemberships, error) {
+	return DefaultClient.GetUserWithMemberships(ctx, opts)
+}
+
 func GetInvitation(
 	ctx context.Context,
 	opts GetInvitationOpts,
@@ -280,3 +420,21 @@ func GetLogoutURL(opts GetLogoutURLOpts) (*url.URL, error) {
 func RevokeSession(ctx context.Context, opts RevokeSessionOpts) error {
 	return DefaultClient.RevokeSession(ctx, opts)
 }
+
+// LoadSessionFromRequest unseals and validates the session cookie of the given
+// request, refreshing the access token when it has expired.
+func LoadSessionFromRequest(r *http.Request, opts LoadSessionOpts) (LoadSessionResponse, error) {
+	return DefaultClient.LoadSessionFromRequest(r, opts)
+}
+
+// SessionMiddleware returns a middleware that loads, refreshes and attaches the
+// session of each request to its context.
+func SessionMiddleware(opts SessionMiddlewareOpts) func(http.Handler) http.Handler {
+	return DefaultClient.SessionMiddleware(opts)
+}
+
+// GetStepUpURL generates an authorization URL for the user of the given
+// session to authenticate again.
+func GetStepUpURL(opts GetStepUpURLOpts) (*url.URL, error) {
+	return DefaultClient.GetStepUpURL(opts)
+}
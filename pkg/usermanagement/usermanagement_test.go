@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/workos/workos-go/v4/pkg/common"
 	"github.com/workos/workos-go/v4/pkg/mfa"
@@ -33,8 +34,8 @@ func TestUserManagementGetUser(t *testing.T) {
 		FirstName:     "Marcelina",
 		LastName:      "Davis",
 		EmailVerified: true,
-		CreatedAt:     "2021-06-25T19:07:33.155Z",
-		UpdatedAt:     "2021-06-25T19:07:33.155Z",
+		CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+		UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 	}
 
 	userRes, err := GetUser(context.Background(), GetUserOpts{
@@ -62,8 +63,8 @@ func TestUserManagementListUsers(t *testing.T) {
 				FirstName:     "Marcelina",
 				LastName:      "Davis",
 				EmailVerified: true,
-				CreatedAt:     "2021-06-25T19:07:33.155Z",
-				UpdatedAt:     "2021-06-25T19:07:33.155Z",
+				CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+				UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 			},
 		},
 		ListMetadata: common.ListMetadata{
@@ -91,8 +92,8 @@ func TestUserManagementCreateUser(t *testing.T) {
 		FirstName:     "Marcelina",
 		LastName:      "Davis",
 		EmailVerified: true,
-		CreatedAt:     "2021-06-25T19:07:33.155Z",
-		UpdatedAt:     "2021-06-25T19:07:33.155Z",
+		CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+		UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 	}
 
 	userRes, err := CreateUser(context.Background(), CreateUserOpts{
@@ -121,8 +122,8 @@ func TestUserManagementUpdateUser(t *testing.T) {
 		FirstName:     "Marcelina",
 		LastName:      "Davis",
 		EmailVerified: true,
-		CreatedAt:     "2021-06-25T19:07:33.155Z",
-		UpdatedAt:     "2021-06-25T19:07:33.155Z",
+		CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+		UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 	}
 
 	userRes, err := UpdateUser(context.Background(), UpdateUserOpts{
@@ -151,8 +152,8 @@ func TestUserManagementUpdateUserPasswordHash(t *testing.T) {
 		FirstName:     "Marcelina",
 		LastName:      "Davis",
 		EmailVerified: true,
-		CreatedAt:     "2021-06-25T19:07:33.155Z",
-		UpdatedAt:     "2021-06-25T19:07:33.155Z",
+		CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+		UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 	}
 
 	userRes, err := UpdateUser(context.Background(), UpdateUserOpts{
@@ -199,8 +200,8 @@ func TestUsersSendVerificationEmail(t *testing.T) {
 			FirstName:     "Marcelina",
 			LastName:      "Davis",
 			EmailVerified: true,
-			CreatedAt:     "2021-06-25T19:07:33.155Z",
-			UpdatedAt:     "2021-06-25T19:07:33.155Z",
+			CreatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
+			UpdatedAt:     time.Date(2021, time.June, 25, 19, 7, 33, 155000000, time.UTC),
 		},
 	}
 
@@ -5,7 +5,25 @@ import (
 	"net/http"
 )
 
+// ErrMissingAPIKey is returned before sending a request that requires the
+// WorkOS API key when the client has none.
+var ErrMissingAPIKey = errors.New("missing API key: set the APIKey of the client")
+
+// ErrNotFound is matched with errors.Is by the HTTPError returned when WorkOS
+// responds with 404 Not Found, e.g. when getting or deleting a record that
+// does not exist.
+var ErrNotFound = errors.New("not found")
+
 func IsBadRequest(err error) bool {
 	var httpError HTTPError
 	return errors.As(err, &httpError) && httpError.Code == http.StatusBadRequest
 }
+
+func IsUnauthorized(err error) bool {
+	var httpError HTTPError
+	return errors.As(err, &httpError) && httpError.Code == http.StatusUnauthorized
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
@@ -1,6 +1,7 @@
 package workos_errors_test
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -50,3 +51,86 @@ func TestIsBadRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestIsUnauthorized(t *testing.T) {
+	type args struct {
+		err error
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "unauthorized",
+			args: args{err: workos_errors.HTTPError{
+				Code: http.StatusUnauthorized,
+			}},
+			want: true,
+		},
+		{
+			name: "wrapped unauthorized",
+			args: args{err: fmt.Errorf("ping: %w", workos_errors.HTTPError{
+				Code: http.StatusUnauthorized,
+			})},
+			want: true,
+		},
+		{
+			name: "bad request",
+			args: args{err: workos_errors.HTTPError{
+				Code: http.StatusBadRequest,
+			}},
+			want: false,
+		},
+		{
+			name: "nil",
+			args: args{err: nil},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := workos_errors.IsUnauthorized(tt.args.err); got != tt.want {
+				t.Errorf("IsUnauthorized() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "not found",
+			err:  workos_errors.HTTPError{Code: http.StatusNotFound},
+			want: true,
+		},
+		{
+			name: "wrapped not found",
+			err:  fmt.Errorf("get user: %w", workos_errors.HTTPError{Code: http.StatusNotFound}),
+			want: true,
+		},
+		{
+			name: "bad request",
+			err:  workos_errors.HTTPError{Code: http.StatusBadRequest},
+			want: false,
+		},
+		{
+			name: "nil",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, workos_errors.ErrNotFound); got != tt.want {
+				t.Errorf("errors.Is(ErrNotFound) = %v, want %v", got, tt.want)
+			}
+			if got := workos_errors.IsNotFound(tt.err); got != tt.want {
+				t.Errorf("IsNotFound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
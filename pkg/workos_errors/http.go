@@ -94,11 +94,20 @@ type HTTPError struct {
 	FieldErrors []FieldError
 }
 
+// FieldError represents a validation error on a single field of a request,
+// as returned in the body of 422 responses.
 type FieldError struct {
-	Field string
-	Code  string
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 func (e HTTPError) Error() string {
 	return fmt.Sprintf("%s: request id %q: %s", e.Status, e.RequestID, e.Message)
 }
+
+// Is reports whether the error matches target, so that a 404 HTTPError
+// matches ErrNotFound.
+func (e HTTPError) Is(target error) bool {
+	return target == ErrNotFound && e.Code == http.StatusNotFound
+}
@@ -68,6 +68,32 @@ func TestGetHTTPErrorWith422StatusCodeJSONPayload(t *testing.T) {
 	t.Log(httperr)
 }
 
+func TestGetHTTPErrorWith422StatusCodeMultipleFieldErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-ID", "GOrOXx")
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteHeader(http.StatusUnprocessableEntity)
+	rec.WriteString(`{
+		"message": "Validation failed",
+		"code": "invalid_request_parameters",
+		"errors": [
+			{"field": "email", "code": "invalid", "message": "email must be a valid email address"},
+			{"field": "password", "code": "too_short", "message": "password must be at least 10 characters"}
+		]
+	}`)
+
+	err := TryGetHTTPError(rec.Result())
+	require.Error(t, err)
+
+	httperr := err.(HTTPError)
+	require.Equal(t, "Validation failed", httperr.Message)
+	require.Equal(t, "invalid_request_parameters", httperr.ErrorCode)
+	require.Equal(t, []FieldError{
+		{Field: "email", Code: "invalid", Message: "email must be a valid email address"},
+		{Field: "password", Code: "too_short", Message: "password must be at least 10 characters"},
+	}, httperr.FieldErrors)
+}
+
 func TestGetHTTPErrorWithInvalidJSONPayload(t *testing.T) {
 	rec := httptest.NewRecorder()
 	rec.Header().Set("X-Request-ID", "GOrOXx")